@@ -0,0 +1,12 @@
+data "flagsmith_environment_diff" "staging_production" {
+  source_environment_key = "<staging_environment_key>"
+  target_environment_key = "<production_environment_key>"
+}
+
+# Warn when production diverges from staging
+check "production_matches_staging" {
+  assert {
+    condition     = data.flagsmith_environment_diff.staging_production.identical
+    error_message = "Production differs from staging for: ${join(", ", [for name, feature in data.flagsmith_environment_diff.staging_production.features : name if !feature.identical])}"
+  }
+}
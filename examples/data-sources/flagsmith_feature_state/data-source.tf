@@ -0,0 +1,17 @@
+data "flagsmith_feature_state" "rollout_percentage" {
+  environment_key = "<environment_key>"
+  feature_name    = "rollout_percentage"
+}
+
+module "autoscaling" {
+  source = "./modules/autoscaling"
+
+  canary_percentage = data.flagsmith_feature_state.rollout_percentage.value
+}
+
+# Read the segment override of a segment instead of the environment default
+data "flagsmith_feature_state" "rollout_percentage_beta" {
+  environment_uuid = "<environment_uuid>"
+  feature_name     = "rollout_percentage"
+  segment_id       = 12
+}
@@ -0,0 +1,8 @@
+data "flagsmith_identity" "qa" {
+  environment_key = "<environment_key>"
+  identifier      = "qa@example.com"
+}
+
+output "qa_checkout_v2_enabled" {
+  value = data.flagsmith_identity.qa.flags["checkout_v2"].enabled
+}
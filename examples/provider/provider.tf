@@ -1,4 +1,11 @@
 provider "flagsmith" {
   # or omit this for master_api_key to be read from environment variable
   master_api_key = "<Master API Key>"
+
+  # optional: the values of the features tagged "sensitive" must be set with
+  # sensitive_string_value, and are never shown in the plan output
+  sensitive_feature_tag = "sensitive"
+
+  # optional: apply the tag in these environments only
+  sensitive_feature_environment_keys = ["<Production Environment Key>"]
 }
@@ -0,0 +1,4 @@
+import {
+  to = flagsmith_environment_feature_states.dev
+  id = "<environment_client_key>"
+}
@@ -0,0 +1 @@
+terraform import flagsmith_environment_feature_states.dev <environment_client_key>
@@ -0,0 +1,25 @@
+resource "flagsmith_environment_feature_states" "dev" {
+  environment_key = "<environment_key>"
+
+  # Set to true to reset every other feature of the project to its default in this environment
+  exclusive = false
+
+  feature_states = {
+    # Features can be referenced by name...
+    new_standard_feature = {
+      enabled = true
+      feature_state_value = {
+        type         = "unicode"
+        string_value = "some_flag_value"
+      }
+    }
+    # ...or by ID
+    (flagsmith_feature.max_items.id) = {
+      enabled = true
+      feature_state_value = {
+        type          = "int"
+        integer_value = 10
+      }
+    }
+  }
+}
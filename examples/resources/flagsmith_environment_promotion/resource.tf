@@ -0,0 +1,29 @@
+# Copy the flag configuration of the features tagged `release` from staging to production
+resource "flagsmith_environment_promotion" "staging_to_production" {
+  source_environment_key       = "<staging_environment_key>"
+  target_environment_key       = "<production_environment_key>"
+  tags                         = ["release"]
+  include_segment_overrides    = true
+  include_multivariate_weights = true
+}
+
+# Promote the changes made to staging by the same apply
+resource "flagsmith_feature_state" "checkout_v2_staging" {
+  enabled         = true
+  environment_key = "<staging_environment_key>"
+  feature_id      = 42
+  feature_state_value = {
+    type         = "unicode"
+    string_value = "variant_b"
+  }
+}
+
+resource "flagsmith_environment_promotion" "checkout_v2" {
+  source_environment_key = flagsmith_feature_state.checkout_v2_staging.environment_key
+  target_environment_key = "<production_environment_key>"
+  feature_ids            = [flagsmith_feature_state.checkout_v2_staging.feature_id]
+  triggers = {
+    enabled = flagsmith_feature_state.checkout_v2_staging.enabled
+    value   = flagsmith_feature_state.checkout_v2_staging.feature_state_value.string_value
+  }
+}
@@ -12,3 +12,102 @@ resource "flagsmith_feature" "new_standard_feature" {
   type         = "STANDARD"
 }
 
+
+# Archive the feature instead of deleting it when the resource is destroyed
+resource "flagsmith_feature" "checkout_v2" {
+  feature_name  = "checkout_v2"
+  project_uuid  = "10421b1f-5f29-4da9-abe2-30f88c07c9e8"
+  description   = "New checkout flow"
+  type          = "STANDARD"
+  deletion_mode = "archive"
+}
+
+# Owners can be referred to by email and group owners by name
+resource "flagsmith_feature" "payments_retry" {
+  feature_name      = "payments_retry"
+  project_uuid      = "10421b1f-5f29-4da9-abe2-30f88c07c9e8"
+  type              = "STANDARD"
+  owner_emails      = ["alice@example.com"]
+  group_owner_names = ["payments"]
+}
+
+# Tags can be attached by name, creating the missing ones
+resource "flagsmith_feature" "search_v2" {
+  feature_name        = "search_v2"
+  project_uuid        = "10421b1f-5f29-4da9-abe2-30f88c07c9e8"
+  type                = "STANDARD"
+  tag_names           = ["experiment", "search"]
+  create_missing_tags = true
+}
+
+# Read how the feature is served in every environment of the project
+resource "flagsmith_feature" "dark_mode" {
+  feature_name               = "dark_mode"
+  project_uuid               = "10421b1f-5f29-4da9-abe2-30f88c07c9e8"
+  type                       = "STANDARD"
+  include_environment_states = true
+}
+
+output "dark_mode_enabled" {
+  value = { for name, state in flagsmith_feature.dark_mode.environment_states : name => state.enabled }
+}
+
+# Declare the feature along with its feature states in some environments
+resource "flagsmith_feature" "max_upload_size" {
+  feature_name = "max_upload_size"
+  project_uuid = "10421b1f-5f29-4da9-abe2-30f88c07c9e8"
+  type         = "STANDARD"
+
+  environment_values = {
+    "<development_environment_key>" = {
+      enabled = true
+      feature_state_value = {
+        type          = "int"
+        integer_value = 100
+      }
+    }
+    "<production_environment_key>" = {
+      enabled = true
+      feature_state_value = {
+        type          = "int"
+        integer_value = 20
+      }
+    }
+  }
+}
+
+# Set the type of the initial value instead of having it inferred, here a
+# string that would otherwise be an integer
+resource "flagsmith_feature" "api_version" {
+  feature_name = "api_version"
+  project_uuid = "10421b1f-5f29-4da9-abe2-30f88c07c9e8"
+  type         = "STANDARD"
+
+  initial_feature_state_value = {
+    type         = "unicode"
+    string_value = "2"
+  }
+}
+
+# Set the metadata of the feature, keyed by metadata field name
+resource "flagsmith_feature" "checkout_v2" {
+  feature_name = "checkout_v2"
+  project_uuid = "10421b1f-5f29-4da9-abe2-30f88c07c9e8"
+  type         = "STANDARD"
+
+  metadata = {
+    jira_ticket = "https://example.atlassian.net/browse/FS-42"
+  }
+}
+
+# Leave the tags and owners attached by flagsmith_feature_tag and
+# flagsmith_feature_owner resources of other workspaces untouched
+resource "flagsmith_feature" "checkout" {
+  feature_name = "checkout"
+  project_uuid = "10421b1f-5f29-4da9-abe2-30f88c07c9e8"
+  type         = "STANDARD"
+
+  tag_names            = ["payments"]
+  tags_authoritative   = false
+  owners_authoritative = false
+}
@@ -0,0 +1,2 @@
+terraform import flagsmith_feature_owner.checkout_security_lead <feature_uuid>,user,<user_id>
+terraform import flagsmith_feature_owner.checkout_security_team <feature_uuid>,group,<group_id>
@@ -0,0 +1,11 @@
+# Add a user and a group to the owners of a feature declared in another
+# workspace, which sets owners_authoritative = false
+resource "flagsmith_feature_owner" "checkout_security_lead" {
+  feature_uuid = "3f6e1a3c-3b9a-4d2a-9a0b-1c4e5f6a7b8c"
+  user_id      = 3936
+}
+
+resource "flagsmith_feature_owner" "checkout_security_team" {
+  feature_uuid = "3f6e1a3c-3b9a-4d2a-9a0b-1c4e5f6a7b8c"
+  group_id     = 42
+}
@@ -17,32 +17,38 @@ resource "flagsmith_feature_state" "feature_1_dev" {
 
 }
 
-resource "flagsmith_segment" "device_type_segment" {
-  name         = "device_type"
-  project_uuid = "10421b1f-5f29-4da9-abe2-30f88c07c9e8"
-  rules = [
-    {
-      "rules" : [{
-        "conditions" : [{
-          "operator" : "EQUAL",
-          "property" : "device_type",
-          "value" : "mobile"
-        }],
-        "type" : "ANY"
-      }],
-      "type" : "ALL"
-    }
-  ]
+# Goes live at midnight through a scheduled change
+resource "flagsmith_feature_state" "feature_1_prod" {
+  enabled         = true
+  environment_key = "<environment_key>"
+  feature_id      = flagsmith_feature.new_standard_feature.id
+  live_from       = "2030-01-01T00:00:00Z"
+  feature_state_value = {
+    type         = "unicode"
+    string_value = "launch_value"
+  }
 }
 
-resource "flagsmith_feature_state" "feature_1_dev_segment_override" {
-  enabled          = true
-  environment_key  = "<environment_key>"
-  feature_id       = flagsmith_feature.new_standard_feature.id
-  segment_id       = flagsmith_segment.device_type_segment.id
-  segment_priority = 0
+# Terraform owns whether the feature is enabled, the value is seeded on create
+# and can then be changed in the Flagsmith dashboard
+resource "flagsmith_feature_state" "feature_1_staging" {
+  enabled         = true
+  environment_key = "<environment_key>"
+  feature_id      = flagsmith_feature.new_standard_feature.id
+  managed_fields  = ["enabled"]
   feature_state_value = {
     type         = "unicode"
-    string_value = "segment_override_value"
+    string_value = "initial_value"
+  }
+}
+
+# Hidden from the plan output
+resource "flagsmith_feature_state" "partner_api_token_prod" {
+  enabled         = true
+  environment_key = "<environment_key>"
+  feature_id      = flagsmith_feature.new_standard_feature.id
+  feature_state_value = {
+    type                   = "unicode"
+    sensitive_string_value = var.partner_api_token
   }
 }
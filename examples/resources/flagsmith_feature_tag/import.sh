@@ -0,0 +1 @@
+terraform import flagsmith_feature_tag.checkout_pii <feature_uuid>,<tag_id>
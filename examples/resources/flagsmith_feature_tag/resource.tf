@@ -0,0 +1,12 @@
+# Attach a tag to a feature declared in another workspace, which sets
+# tags_authoritative = false
+resource "flagsmith_tag" "pii" {
+  tag_name     = "pii"
+  tag_colour   = "#FF0000"
+  project_uuid = "10421b1f-5f29-4da9-abe2-30f88c07c9e8"
+}
+
+resource "flagsmith_feature_tag" "checkout_pii" {
+  feature_uuid = "3f6e1a3c-3b9a-4d2a-9a0b-1c4e5f6a7b8c"
+  tag_id       = flagsmith_tag.pii.id
+}
@@ -0,0 +1,4 @@
+import {
+  to = flagsmith_identity.qa
+  id = "<environment_client_key>/<identifier>"
+}
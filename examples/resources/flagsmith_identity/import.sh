@@ -0,0 +1 @@
+terraform import flagsmith_identity.qa <environment_client_key>/<identifier>
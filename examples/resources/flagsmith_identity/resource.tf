@@ -0,0 +1,10 @@
+resource "flagsmith_identity" "qa" {
+  environment_key = "<environment_key>"
+  identifier      = "qa@example.com"
+  traits = {
+    plan        = { string_value = "enterprise" }
+    seats       = { integer_value = 25 }
+    nps         = { float_value = 8.5 }
+    beta_tester = { boolean_value = true }
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = flagsmith_identity_override.checkout_v2_qa
+  id = "<environment_client_key>/<identifier>/<feature_name>"
+}
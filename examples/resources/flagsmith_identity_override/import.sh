@@ -0,0 +1 @@
+terraform import flagsmith_identity_override.checkout_v2_qa <environment_client_key>/<identifier>/<feature_name>
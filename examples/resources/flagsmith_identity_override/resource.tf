@@ -0,0 +1,18 @@
+resource "flagsmith_feature" "checkout_v2" {
+  feature_name = "checkout_v2"
+  project_uuid = "10421b1f-5f29-4da9-abe2-30f88c07c9e8"
+  description  = "New checkout flow"
+  type         = "STANDARD"
+}
+
+# Pin the QA identity to the new checkout flow
+resource "flagsmith_identity_override" "checkout_v2_qa" {
+  environment_key = "<environment_key>"
+  identifier      = "qa@example.com"
+  feature_id      = flagsmith_feature.checkout_v2.id
+  enabled         = true
+  feature_state_value = {
+    type         = "unicode"
+    string_value = "variant_b"
+  }
+}
@@ -0,0 +1 @@
+terraform import flagsmith_metadata_field.jira_ticket <metadata_field_id>
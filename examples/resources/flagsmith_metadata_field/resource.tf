@@ -0,0 +1,10 @@
+data "flagsmith_organisation" "my_org" {
+  uuid = "e6b2fa02-64ea-4cc9-9d1d-9e0e8a1ab6f3"
+}
+
+resource "flagsmith_metadata_field" "jira_ticket" {
+  organisation_id = data.flagsmith_organisation.my_org.id
+  name            = "jira_ticket"
+  type            = "url"
+  description     = "Link to the ticket tracking the flag"
+}
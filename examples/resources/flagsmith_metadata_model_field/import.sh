@@ -0,0 +1 @@
+terraform import flagsmith_metadata_model_field.feature_jira_ticket <organisation_id>,<metadata_model_field_id>
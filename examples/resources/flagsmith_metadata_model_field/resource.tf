@@ -0,0 +1,7 @@
+# Attach the jira_ticket field to features, and require it in a project
+resource "flagsmith_metadata_model_field" "feature_jira_ticket" {
+  organisation_id          = flagsmith_metadata_field.jira_ticket.organisation_id
+  field_id                 = flagsmith_metadata_field.jira_ticket.id
+  content_type             = "feature"
+  required_for_project_ids = [42]
+}
@@ -11,3 +11,10 @@ resource "flagsmith_mv_feature_option" "feature_1_mv_option" {
   string_value                  = "option_value_60_percent_of_the_times"
   default_percentage_allocation = 60
 }
+
+resource "flagsmith_mv_feature_option" "feature_1_secret_mv_option" {
+  type                          = "unicode"
+  feature_uuid                  = flagsmith_feature.feature_1.uuid
+  sensitive_string_value        = var.secret_option_value
+  default_percentage_allocation = 40
+}
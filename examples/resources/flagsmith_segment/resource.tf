@@ -46,3 +46,26 @@ resource "flagsmith_segment" "new_standard_feature_specific_segment" {
   ]
 
 }
+
+# Set the metadata of the segment, keyed by metadata field name
+resource "flagsmith_segment" "beta_testers" {
+  name         = "beta_testers"
+  project_uuid = "10421b1f-5f29-4da9-abe2-30f88c07c9e8"
+  rules = [
+    {
+      "rules" : [{
+        "conditions" : [{
+          "operator" : "EQUAL",
+          "property" : "beta",
+          "value" : "true"
+        }],
+        "type" : "ANY"
+      }],
+      "type" : "ALL"
+    }
+  ]
+
+  metadata = {
+    jira_ticket = "https://example.atlassian.net/browse/FS-43"
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = flagsmith_segment_override.some_override
+  id = "<environment_client_key>,<feature_id>,<segment_id>"
+}
@@ -0,0 +1 @@
+terraform import flagsmith_segment_override.some_override <environment_client_key>,<feature_id>,<segment_id>
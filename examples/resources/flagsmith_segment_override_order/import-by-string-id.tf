@@ -0,0 +1,4 @@
+import {
+  to = flagsmith_segment_override_order.feature_1_dev
+  id = "<environment_client_key>,<feature_id>"
+}
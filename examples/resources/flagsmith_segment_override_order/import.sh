@@ -0,0 +1 @@
+terraform import flagsmith_segment_override_order.feature_1_dev <environment_client_key>,<feature_id>
@@ -0,0 +1,31 @@
+resource "flagsmith_segment_override" "feature_1_dev_mobile" {
+  enabled         = true
+  environment_key = "<environment_key>"
+  feature_id      = flagsmith_feature.new_standard_feature.id
+  segment_id      = flagsmith_segment.device_type_segment.id
+  feature_state_value = {
+    type         = "unicode"
+    string_value = "mobile_value"
+  }
+}
+
+resource "flagsmith_segment_override" "feature_1_dev_beta" {
+  enabled         = true
+  environment_key = "<environment_key>"
+  feature_id      = flagsmith_feature.new_standard_feature.id
+  segment_id      = flagsmith_segment.beta_users_segment.id
+  feature_state_value = {
+    type         = "unicode"
+    string_value = "beta_value"
+  }
+}
+
+# Referencing the segment overrides makes sure they exist before being ordered
+resource "flagsmith_segment_override_order" "feature_1_dev" {
+  environment_key = "<environment_key>"
+  feature_id      = flagsmith_feature.new_standard_feature.id
+  segment_ids = [
+    flagsmith_segment_override.feature_1_dev_beta.segment_id,
+    flagsmith_segment_override.feature_1_dev_mobile.segment_id,
+  ]
+}
@@ -0,0 +1,111 @@
+package flagsmith
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// featureStateCache holds a snapshot of the feature states of every environment
+// read during a Terraform operation, so that refreshing many feature states of
+// the same environment only costs a single listing.
+type featureStateCache struct {
+	mu           sync.Mutex
+	environments map[string]*featureStateSnapshot
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type featureStateSnapshot struct {
+	ready chan struct{}
+	err   error
+
+	byUUID    map[string]*flagsmithapi.FeatureState
+	byFeature map[int64]*flagsmithapi.FeatureState
+}
+
+func newFeatureStateCache() *featureStateCache {
+	return &featureStateCache{environments: map[string]*featureStateSnapshot{}}
+}
+
+// snapshot returns the snapshot of the given environment, fetching it with
+// fetch if there is none. Concurrent callers wait for the same fetch.
+func (c *featureStateCache) snapshot(environmentKey string, fetch func() ([]flagsmithapi.FeatureState, error)) *featureStateSnapshot {
+	c.mu.Lock()
+	snapshot, ok := c.environments[environmentKey]
+	if !ok {
+		snapshot = &featureStateSnapshot{ready: make(chan struct{})}
+		c.environments[environmentKey] = snapshot
+	}
+	c.mu.Unlock()
+
+	if ok {
+		<-snapshot.ready
+		return snapshot
+	}
+
+	featureStates, err := fetch()
+	snapshot.err = err
+	snapshot.byUUID = map[string]*flagsmithapi.FeatureState{}
+	snapshot.byFeature = map[int64]*flagsmithapi.FeatureState{}
+	for i := range featureStates {
+		snapshot.byUUID[featureStates[i].UUID] = &featureStates[i]
+		snapshot.byFeature[featureStates[i].Feature] = &featureStates[i]
+	}
+	close(snapshot.ready)
+
+	if err != nil {
+		// Let the next caller try again
+		c.mu.Lock()
+		if c.environments[environmentKey] == snapshot {
+			delete(c.environments, environmentKey)
+		}
+		c.mu.Unlock()
+	}
+	return snapshot
+}
+
+// Get returns a copy of the cached feature state of the environment matching
+// the given uuid, or the given feature if uuid is empty. The second return
+// value is false on a miss.
+func (c *featureStateCache) Get(ctx context.Context, environmentKey, uuid string, featureID int64, fetch func() ([]flagsmithapi.FeatureState, error)) (*flagsmithapi.FeatureState, bool) {
+	snapshot := c.snapshot(environmentKey, fetch)
+
+	var featureState *flagsmithapi.FeatureState
+	if snapshot.err == nil {
+		if uuid != "" {
+			featureState = snapshot.byUUID[uuid]
+		} else {
+			featureState = snapshot.byFeature[featureID]
+		}
+	}
+	if featureState == nil {
+		misses := c.misses.Add(1)
+		tflog.Debug(ctx, "Feature state cache miss", map[string]interface{}{
+			"environment_key": environmentKey, "hits": c.hits.Load(), "misses": misses,
+		})
+		return nil, false
+	}
+	hits := c.hits.Add(1)
+	tflog.Debug(ctx, "Feature state cache hit", map[string]interface{}{
+		"environment_key": environmentKey, "hits": hits, "misses": c.misses.Load(),
+	})
+	featureStateCopy := *featureState
+	return &featureStateCopy, true
+}
+
+// Invalidate drops the snapshot of the given environment, or of every
+// environment if environmentKey is empty
+func (c *featureStateCache) Invalidate(environmentKey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if environmentKey == "" {
+		c.environments = map[string]*featureStateSnapshot{}
+		return
+	}
+	delete(c.environments, environmentKey)
+}
@@ -0,0 +1,85 @@
+package flagsmith
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureStateCacheFetchesOncePerEnvironment(t *testing.T) {
+	// Given
+	cache := newFeatureStateCache()
+	var fetches atomic.Int64
+	fetch := func() ([]flagsmithapi.FeatureState, error) {
+		fetches.Add(1)
+		return []flagsmithapi.FeatureState{
+			{ID: 1, UUID: "uuid-1", Feature: 10},
+			{ID: 2, UUID: "uuid-2", Feature: 20},
+		}, nil
+	}
+	var wg sync.WaitGroup
+
+	// When
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			featureState, ok := cache.Get(context.Background(), "env", "uuid-2", 0, fetch)
+			assert.True(t, ok)
+			assert.Equal(t, int64(2), featureState.ID)
+		}()
+	}
+	wg.Wait()
+	featureState, ok := cache.Get(context.Background(), "env", "", 10, fetch)
+	_, missed := cache.Get(context.Background(), "env", "unknown-uuid", 0, fetch)
+
+	// Then
+	assert.Equal(t, int64(1), fetches.Load())
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), featureState.ID)
+	assert.False(t, missed)
+	assert.Equal(t, int64(21), cache.hits.Load())
+	assert.Equal(t, int64(1), cache.misses.Load())
+}
+
+func TestFeatureStateCacheInvalidate(t *testing.T) {
+	// Given
+	cache := newFeatureStateCache()
+	fetches := 0
+	fetch := func() ([]flagsmithapi.FeatureState, error) {
+		fetches++
+		return []flagsmithapi.FeatureState{{ID: 1, UUID: "uuid-1", Feature: 10}}, nil
+	}
+	cache.Get(context.Background(), "env", "uuid-1", 0, fetch)
+
+	// When
+	cache.Invalidate("env")
+	cache.Get(context.Background(), "env", "uuid-1", 0, fetch)
+
+	// Then
+	assert.Equal(t, 2, fetches)
+}
+
+func TestFeatureStateCacheRetriesAfterError(t *testing.T) {
+	// Given
+	cache := newFeatureStateCache()
+	fetch := func() ([]flagsmithapi.FeatureState, error) {
+		return nil, errors.New("boom")
+	}
+
+	// When
+	_, ok := cache.Get(context.Background(), "env", "uuid-1", 0, fetch)
+	featureState, retried := cache.Get(context.Background(), "env", "uuid-1", 0, func() ([]flagsmithapi.FeatureState, error) {
+		return []flagsmithapi.FeatureState{{ID: 1, UUID: "uuid-1", Feature: 10}}, nil
+	})
+
+	// Then
+	assert.False(t, ok)
+	assert.True(t, retried)
+	assert.Equal(t, int64(1), featureState.ID)
+}
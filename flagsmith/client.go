@@ -0,0 +1,1014 @@
+package flagsmith
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/go-resty/resty/v2"
+)
+
+// fsClient is handed to resources and data sources as provider data. It embeds
+// the flagsmith api client and adds the endpoints that are not (yet) exposed
+// by it, along with the state shared by all the resources of a provider.
+type fsClient struct {
+	*flagsmithapi.Client
+
+	baseURL string
+	rest    *resty.Client
+
+	// segmentOverrideLocks serialises the writes to the segment overrides of a
+	// feature in an environment, since Flagsmith reorders the priorities of all
+	// of them whenever one is created, updated or deleted.
+	segmentOverrideLocks *keyedMutex
+
+	// featureTagLocks serialises the updates of the tags of a feature, keyed
+	// by feature UUID, since they are read, changed and written back whole
+	featureTagLocks *keyedMutex
+
+	// featureStates caches the feature states of the environments, nil if
+	// the cache is disabled
+	featureStates *featureStateCache
+
+	// changeRequestMode is the `change_request_mode` used by the resources
+	// that do not set their own
+	changeRequestMode string
+
+	// versionedEnvironments caches whether the environments use v2 feature
+	// versioning, keyed by environment key
+	versionedEnvironments sync.Map
+
+	// sensitiveFeatureTag is the label of the tag marking the features whose
+	// string values are sensitive, empty if not set
+	sensitiveFeatureTag string
+
+	// sensitiveEnvironmentKeys holds the keys of the environments in which the
+	// sensitiveFeatureTag applies, nil if it applies in every environment
+	sensitiveEnvironmentKeys map[string]bool
+
+	// sensitiveFeatures caches the IDs of the features carrying the
+	// sensitiveFeatureTag, keyed by project ID
+	sensitiveFeatures sync.Map
+
+	// featureRules caches the feature rules of the projects, keyed
+	// by project UUID. Projects planned for an update store their planned
+	// rules.
+	featureRules sync.Map
+
+	// plannedFeatureRules holds the planned feature rules of the projects
+	// created in the current plan, whose UUIDs are unknown until they are
+	// applied, keyed by organisation ID and project name
+	plannedFeatureRules sync.Map
+
+	// organisationMembers caches the users and groups of the organisations,
+	// keyed by organisation ID
+	organisationMembers sync.Map
+
+	// projectTags caches the tags of the projects, keyed by project ID
+	projectTags sync.Map
+
+	// featureStateManagers records which resource manages the feature state
+	// of a feature in an environment, keyed by environment key and feature ID
+	featureStateManagers sync.Map
+
+	// organisationMetadata caches the metadata fields of the organisations,
+	// keyed by organisation ID
+	organisationMetadata sync.Map
+}
+
+func newFSClient(masterAPIKey string, baseURL string) *fsClient {
+	return newFSClientWithCache(masterAPIKey, baseURL, false)
+}
+
+func newFSClientWithCache(masterAPIKey string, baseURL string, cacheFeatureStates bool) *fsClient {
+	c := &fsClient{
+		Client:  flagsmithapi.NewClient(masterAPIKey, baseURL),
+		baseURL: baseURL,
+		rest:    resty.New(),
+
+		segmentOverrideLocks: newKeyedMutex(),
+		featureTagLocks:      newKeyedMutex(),
+		changeRequestMode:    ChangeRequestModeNever,
+	}
+	if cacheFeatureStates {
+		c.featureStates = newFeatureStateCache()
+	}
+	c.rest.SetHeaders(map[string]string{
+		"Accept":        "application/json",
+		"Content-type":  "application/json",
+		"Authorization": "Api-Key " + masterAPIKey,
+	})
+	return c
+}
+
+// LockSegmentOverrides blocks until no other segment override write is in
+// progress for the feature in the given environment. The returned function
+// releases the lock.
+func (c *fsClient) LockSegmentOverrides(environmentKey string, featureID int64) func() {
+	key := fmt.Sprintf("%s/%d", environmentKey, featureID)
+	c.segmentOverrideLocks.Lock(key)
+	return func() { c.segmentOverrideLocks.Unlock(key) }
+}
+
+// LockFeatureTags blocks until no other update of the tags of the feature is
+// in progress. The returned function releases the lock.
+func (c *fsClient) LockFeatureTags(featureUUID string) func() {
+	c.featureTagLocks.Lock(featureUUID)
+	return func() { c.featureTagLocks.Unlock(featureUUID) }
+}
+
+// UpdateFeatureTags attaches the tags of add to the feature and detaches the
+// ones of remove, leaving its other tags untouched
+func (c *fsClient) UpdateFeatureTags(featureUUID string, add, remove []int64) error {
+	unlock := c.LockFeatureTags(featureUUID)
+	defer unlock()
+
+	feature, err := c.GetFeature(featureUUID)
+	if err != nil {
+		return err
+	}
+	tags := MergeIDs(feature.Tags, add, remove)
+	if len(Difference(&tags, &feature.Tags)) == 0 && len(Difference(&feature.Tags, &tags)) == 0 {
+		return nil
+	}
+	feature.Tags = tags
+	return c.UpdateFeatureWithMetadata(feature, nil)
+}
+
+// ArchiveFeature archives the feature as it currently is, under the lock of
+// its tags so that the tags attached by other resources are kept
+func (c *fsClient) ArchiveFeature(featureUUID string) error {
+	unlock := c.LockFeatureTags(featureUUID)
+	defer unlock()
+
+	feature, err := c.GetFeature(featureUUID)
+	if err != nil {
+		return err
+	}
+	feature.IsArchived = true
+	return c.UpdateFeatureWithMetadata(feature, nil)
+}
+
+// getAllPages follows the `next` links of a paginated listing and returns the
+// results of all the pages
+func getAllPages[T any](c *fsClient, url string, queryParams map[string]string, what string) ([]T, error) {
+	items := []T{}
+	for url != "" {
+		result := struct {
+			Next    *string `json:"next"`
+			Results []T     `json:"results"`
+		}{}
+		req := c.rest.R().SetResult(&result)
+		// The next links already carry the query params
+		if len(items) == 0 {
+			req.SetQueryParams(queryParams)
+		}
+		resp, err := req.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		if !resp.IsSuccess() {
+			return nil, fmt.Errorf("flagsmithapi: Error getting %s: %s", what, resp)
+		}
+		items = append(items, result.Results...)
+
+		url = ""
+		if result.Next != nil {
+			url = *result.Next
+		}
+	}
+	return items, nil
+}
+
+// Get all the feature segments(i.e: segment overrides) of a feature in a given environment
+func (c *fsClient) GetFeatureSegments(environmentID, featureID int64) ([]flagsmithapi.FeatureSegment, error) {
+	url := fmt.Sprintf("%s/features/feature-segments/", c.baseURL)
+	return getAllPages[flagsmithapi.FeatureSegment](c, url, map[string]string{
+		"environment": strconv.FormatInt(environmentID, 10),
+		"feature":     strconv.FormatInt(featureID, 10),
+	}, "feature segments")
+}
+
+// Get the feature states of all the features of an environment, segment and
+// identity overrides excluded
+func (c *fsClient) GetEnvironmentFeatureStates(environmentKey string) ([]flagsmithapi.FeatureState, error) {
+	url := fmt.Sprintf("%s/environments/%s/featurestates/", c.baseURL, environmentKey)
+	featureStates, err := getAllPages[flagsmithapi.FeatureState](c, url, nil, "environment feature states")
+	if err != nil {
+		return nil, err
+	}
+	for i := range featureStates {
+		featureStates[i].EnvironmentKey = environmentKey
+		// The listing returns the raw value, which can not be typed when null
+		if featureStates[i].FeatureStateValue == nil {
+			featureStates[i].FeatureStateValue = &flagsmithapi.FeatureStateValue{Type: "unicode"}
+		}
+	}
+	return featureStates, nil
+}
+
+// Get the feature state of an environment(i.e: not a segment or identity
+// override) by uuid, or by feature if featureStateUUID is empty. The feature
+// states of the environment are all fetched on the first call and then served
+// from the cache until a feature state of the environment is updated.
+func (c *fsClient) GetCachedEnvironmentFeatureState(ctx context.Context, environmentKey, featureStateUUID string, featureID int64) (*flagsmithapi.FeatureState, error) {
+	if c.featureStates != nil {
+		featureState, ok := c.featureStates.Get(ctx, environmentKey, featureStateUUID, featureID, func() ([]flagsmithapi.FeatureState, error) {
+			return c.GetEnvironmentFeatureStates(environmentKey)
+		})
+		if ok {
+			return featureState, nil
+		}
+	}
+	if featureStateUUID != "" {
+		return c.GetFeatureState(featureStateUUID)
+	}
+	return c.GetEnvironmentFeatureState(environmentKey, featureID)
+}
+
+// invalidateFeatureStates drops the cached feature states of the environment,
+// or of every environment if environmentKey is empty
+func (c *fsClient) invalidateFeatureStates(environmentKey string) {
+	if c.featureStates != nil {
+		c.featureStates.Invalidate(environmentKey)
+	}
+}
+
+// Update the feature state, invalidating the cached feature states of its environment
+func (c *fsClient) UpdateFeatureState(featureState *flagsmithapi.FeatureState, updateSegmentPriority bool) error {
+	defer c.invalidateFeatureStates(featureState.EnvironmentKey)
+	return c.Client.UpdateFeatureState(featureState, updateSegmentPriority)
+}
+
+// Create a segment override, invalidating the cached feature states of its
+// environment
+func (c *fsClient) CreateSegmentOverride(featureState *flagsmithapi.FeatureState) error {
+	defer c.invalidateFeatureStates(featureState.EnvironmentKey)
+	return c.Client.CreateSegmentOverride(featureState)
+}
+
+// Delete a feature segment along with its segment override, invalidating the
+// cached feature states of every environment since its environment is not
+// known
+func (c *fsClient) DeleteFeatureSegment(featureSegmentID int64) error {
+	defer c.invalidateFeatureStates("")
+	return c.Client.DeleteFeatureSegment(featureSegmentID)
+}
+
+// Delete a feature along with its feature states, invalidating the cached
+// feature states of every environment
+func (c *fsClient) DeleteFeature(projectID, featureID int64) error {
+	defer c.invalidateFeatureStates("")
+	return c.Client.DeleteFeature(projectID, featureID)
+}
+
+// Get all the features of a project
+func (c *fsClient) GetProjectFeatures(projectID int64) ([]flagsmithapi.Feature, error) {
+	url := fmt.Sprintf("%s/projects/%d/features/", c.baseURL, projectID)
+	return getAllPages[flagsmithapi.Feature](c, url, nil, "project features")
+}
+
+// Get all the environments of a project
+func (c *fsClient) GetProjectEnvironments(projectID int64) ([]flagsmithapi.Environment, error) {
+	url := fmt.Sprintf("%s/environments/", c.baseURL)
+	return getAllPages[flagsmithapi.Environment](c, url, map[string]string{
+		"project": strconv.FormatInt(projectID, 10),
+	}, "environments")
+}
+
+// Get an environment by ID, looking it up in the environments of every
+// project the client has access to
+func (c *fsClient) GetEnvironmentByID(environmentID int64) (*flagsmithapi.Environment, error) {
+	url := fmt.Sprintf("%s/projects/", c.baseURL)
+	projects := []flagsmithapi.Project{}
+	resp, err := c.rest.R().SetResult(&projects).Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("flagsmithapi: Error getting projects: %s", resp)
+	}
+	for _, project := range projects {
+		environments, err := c.GetProjectEnvironments(project.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range environments {
+			if environments[i].ID == environmentID {
+				return &environments[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("flagsmithapi: environment '%d' not found", environmentID)
+}
+
+// Get all the tags of a project
+func (c *fsClient) GetProjectTags(projectID int64) ([]flagsmithapi.Tag, error) {
+	url := fmt.Sprintf("%s/projects/%d/tags/", c.baseURL, projectID)
+	tags := []flagsmithapi.Tag{}
+	resp, err := c.rest.R().SetResult(&tags).Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("flagsmithapi: Error getting project tags: %s", resp)
+	}
+	return tags, nil
+}
+
+// IsSensitiveFeature reports whether the feature carries the
+// `sensitive_feature_tag` of the provider and the tag applies in the given
+// environment. An empty environment key stands for the values shared by every
+// environment, such as those of the multivariate options. The tagged features
+// of a project are listed once for the lifetime of the provider.
+func (c *fsClient) IsSensitiveFeature(environmentKey string, projectID, featureID int64) (bool, error) {
+	if c.sensitiveFeatureTag == "" {
+		return false, nil
+	}
+	if environmentKey != "" && c.sensitiveEnvironmentKeys != nil && !c.sensitiveEnvironmentKeys[environmentKey] {
+		return false, nil
+	}
+	if sensitive, ok := c.sensitiveFeatures.Load(projectID); ok {
+		return sensitive.(map[int64]bool)[featureID], nil
+	}
+
+	tags, err := c.GetProjectTags(projectID)
+	if err != nil {
+		return false, err
+	}
+	sensitive := map[int64]bool{}
+	for _, tag := range tags {
+		if tag.Name != c.sensitiveFeatureTag || tag.ID == nil {
+			continue
+		}
+		features, err := c.GetProjectFeatures(projectID)
+		if err != nil {
+			return false, err
+		}
+		for _, feature := range features {
+			for _, tagID := range feature.Tags {
+				if tagID == *tag.ID && feature.ID != nil {
+					sensitive[*feature.ID] = true
+				}
+			}
+		}
+		break
+	}
+	c.sensitiveFeatures.Store(projectID, sensitive)
+	return sensitive[featureID], nil
+}
+
+// FeatureRules holds the rules a project enforces on its features
+type FeatureRules struct {
+	// ProjectID is the project the tags of the features are looked up in,
+	// OrganisationID the organisation whose users and groups may own them
+	ProjectName    string
+	ProjectID      int64
+	OrganisationID int64
+
+	OnlyAllowLowerCase bool
+	Regex              string
+	EnforceOwners      bool
+}
+
+// MakeFeatureRules returns the feature rules of a project
+func MakeFeatureRules(project *flagsmithapi.Project) FeatureRules {
+	return FeatureRules{
+		ProjectName:        project.Name,
+		ProjectID:          project.ID,
+		OrganisationID:     project.Organisation,
+		OnlyAllowLowerCase: project.OnlyAllowLowerCaseFeatureNames,
+		Regex:              project.FeatureNameRegex,
+		EnforceOwners:      project.EnforceFeatureOwners != nil && *project.EnforceFeatureOwners,
+	}
+}
+
+// ValidateName returns an error describing the first rule the feature name
+// breaks. Like Flagsmith, the regex must match the whole name.
+func (r FeatureRules) ValidateName(name string) error {
+	if r.OnlyAllowLowerCase && name != strings.ToLower(name) {
+		return fmt.Errorf("project %q only allows lower case feature names (only_allow_lower_case_feature_names), got %q", r.ProjectName, name)
+	}
+	if r.Regex == "" {
+		return nil
+	}
+	regex, err := regexp.Compile("^(?:" + r.Regex + ")$")
+	if err != nil {
+		// Flagsmith uses python regexes, leave the ones we can not parse to it
+		return nil
+	}
+	if !regex.MatchString(name) {
+		return fmt.Errorf("project %q requires feature names to match the regex %q (feature_name_regex), got %q", r.ProjectName, r.Regex, name)
+	}
+	return nil
+}
+
+// ValidateOwners returns an error if the project requires owners and the
+// feature has none, owners counting both users and groups
+func (r FeatureRules) ValidateOwners(owners int) error {
+	if r.EnforceOwners && owners == 0 {
+		return fmt.Errorf("project %q requires every feature to have at least one owner or group owner (enforce_feature_owners)", r.ProjectName)
+	}
+	return nil
+}
+
+// SetFeatureRules overrides the cached feature rules of a project
+func (c *fsClient) SetFeatureRules(projectUUID string, rules FeatureRules) {
+	c.featureRules.Store(projectUUID, rules)
+}
+
+// SetPlannedFeatureRules records the planned feature rules of a project
+// created in the current plan
+func (c *fsClient) SetPlannedFeatureRules(rules FeatureRules) {
+	c.plannedFeatureRules.Store(fmt.Sprintf("%d/%s", rules.OrganisationID, rules.ProjectName), rules)
+}
+
+// GetPlannedFeatureRules returns the planned feature rules of the project
+// created in the current plan. It is false unless exactly one project is
+// created, since the project of a feature with an unknown project UUID can
+// not be told apart otherwise.
+func (c *fsClient) GetPlannedFeatureRules() (FeatureRules, bool) {
+	var planned []FeatureRules
+	c.plannedFeatureRules.Range(func(_, rules any) bool {
+		planned = append(planned, rules.(FeatureRules))
+		return true
+	})
+	if len(planned) != 1 {
+		return FeatureRules{}, false
+	}
+	return planned[0], true
+}
+
+// GetFeatureRules returns the feature rules of a project. The project
+// is read once for the lifetime of the provider.
+func (c *fsClient) GetFeatureRules(projectUUID string) (FeatureRules, error) {
+	if rules, ok := c.featureRules.Load(projectUUID); ok {
+		return rules.(FeatureRules), nil
+	}
+	project, err := c.GetProject(projectUUID)
+	if err != nil {
+		return FeatureRules{}, err
+	}
+	rules := MakeFeatureRules(project)
+	c.featureRules.Store(projectUUID, rules)
+	return rules, nil
+}
+
+// UserGroup is a group of users of an organisation
+type UserGroup struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Get all the user groups of an organisation
+func (c *fsClient) GetOrganisationGroups(orgID int64) ([]UserGroup, error) {
+	url := fmt.Sprintf("%s/organisations/%d/groups/", c.baseURL, orgID)
+	return getAllPages[UserGroup](c, url, nil, "organisation groups")
+}
+
+// organisationMembers indexes the users of an organisation by email and ID,
+// and its groups by name and ID
+type organisationMembers struct {
+	userIDs    map[string]int64
+	userEmails map[int64]string
+	groupIDs   map[string]int64
+	groupNames map[int64]string
+}
+
+// getOrganisationMembers returns the users and groups of an organisation,
+// listed once for the lifetime of the provider unless refresh is set. The
+// second return value is true if they were just listed.
+func (c *fsClient) getOrganisationMembers(orgID int64, refresh bool) (*organisationMembers, bool, error) {
+	if members, ok := c.organisationMembers.Load(orgID); ok && !refresh {
+		return members.(*organisationMembers), false, nil
+	}
+	users, err := c.GetOrganisationUsers(orgID)
+	if err != nil {
+		return nil, false, err
+	}
+	groups, err := c.GetOrganisationGroups(orgID)
+	if err != nil {
+		return nil, false, err
+	}
+	members := &organisationMembers{
+		userIDs:    map[string]int64{},
+		userEmails: map[int64]string{},
+		groupIDs:   map[string]int64{},
+		groupNames: map[int64]string{},
+	}
+	for _, user := range users {
+		members.userIDs[user.Email] = user.ID
+		members.userEmails[user.ID] = user.Email
+	}
+	for _, group := range groups {
+		members.groupIDs[group.Name] = group.ID
+		members.groupNames[group.ID] = group.Name
+	}
+	c.organisationMembers.Store(orgID, members)
+	return members, true, nil
+}
+
+// lookupIndexed maps keys through an index returned by get, which caches it
+// unless refresh is set and reports whether it was just fetched. The index is
+// fetched again on a miss, in case it changed since.
+func lookupIndexed[I any, K comparable, V any](get func(refresh bool) (I, bool, error), keys []K, index func(I) map[K]V, notFound func(missing []K) error) ([]V, error) {
+	refresh := false
+	for {
+		indexed, fresh, err := get(refresh)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]V, 0, len(keys))
+		missing := []K{}
+		for _, key := range keys {
+			if value, ok := index(indexed)[key]; ok {
+				values = append(values, value)
+			} else {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) == 0 {
+			return values, nil
+		}
+		if fresh || refresh {
+			return nil, notFound(missing)
+		}
+		refresh = true
+	}
+}
+
+// lookupOrganisationMembers maps keys through the index of the organisation
+// members returned by index
+func lookupOrganisationMembers[K comparable, V any](c *fsClient, orgID int64, keys []K, index func(*organisationMembers) map[K]V, what string) ([]V, error) {
+	get := func(refresh bool) (*organisationMembers, bool, error) {
+		return c.getOrganisationMembers(orgID, refresh)
+	}
+	return lookupIndexed(get, keys, index, func(missing []K) error {
+		return fmt.Errorf("%s %v not found in organisation %d", what, missing, orgID)
+	})
+}
+
+// GetUserIDs returns the IDs of the users of the organisation with the given
+// emails
+func (c *fsClient) GetUserIDs(orgID int64, emails []string) ([]int64, error) {
+	return lookupOrganisationMembers(c, orgID, emails, func(m *organisationMembers) map[string]int64 { return m.userIDs }, "users")
+}
+
+// GetUserEmails returns the emails of the users of the organisation with the
+// given IDs
+func (c *fsClient) GetUserEmails(orgID int64, userIDs []int64) ([]string, error) {
+	return lookupOrganisationMembers(c, orgID, userIDs, func(m *organisationMembers) map[int64]string { return m.userEmails }, "users")
+}
+
+// GetGroupIDs returns the IDs of the groups of the organisation with the given
+// names
+func (c *fsClient) GetGroupIDs(orgID int64, names []string) ([]int64, error) {
+	return lookupOrganisationMembers(c, orgID, names, func(m *organisationMembers) map[string]int64 { return m.groupIDs }, "groups")
+}
+
+// GetGroupNames returns the names of the groups of the organisation with the
+// given IDs
+func (c *fsClient) GetGroupNames(orgID int64, groupIDs []int64) ([]string, error) {
+	return lookupOrganisationMembers(c, orgID, groupIDs, func(m *organisationMembers) map[int64]string { return m.groupNames }, "groups")
+}
+
+// projectTagIndex indexes the tags of a project by name and ID
+type projectTagIndex struct {
+	tagIDs   map[string]int64
+	tagNames map[int64]string
+}
+
+// getProjectTagIndex returns the tags of a project, listed once for the
+// lifetime of the provider unless refresh is set. The second return value is
+// true if they were just listed.
+func (c *fsClient) getProjectTagIndex(projectID int64, refresh bool) (*projectTagIndex, bool, error) {
+	if index, ok := c.projectTags.Load(projectID); ok && !refresh {
+		return index.(*projectTagIndex), false, nil
+	}
+	tags, err := c.GetProjectTags(projectID)
+	if err != nil {
+		return nil, false, err
+	}
+	index := &projectTagIndex{tagIDs: map[string]int64{}, tagNames: map[int64]string{}}
+	for _, tag := range tags {
+		if tag.ID == nil {
+			continue
+		}
+		index.tagIDs[tag.Name] = *tag.ID
+		index.tagNames[*tag.ID] = tag.Name
+	}
+	c.projectTags.Store(projectID, index)
+	return index, true, nil
+}
+
+// TagNotFoundError is returned when tags are looked up by a name no tag of
+// the project has
+type TagNotFoundError struct {
+	ProjectID int64
+	Names     []string
+}
+
+func (e TagNotFoundError) Error() string {
+	return fmt.Sprintf("tags %v not found in project %d", e.Names, e.ProjectID)
+}
+
+// GetTagIDs returns the IDs of the tags of the project with the given names
+func (c *fsClient) GetTagIDs(projectID int64, names []string) ([]int64, error) {
+	get := func(refresh bool) (*projectTagIndex, bool, error) { return c.getProjectTagIndex(projectID, refresh) }
+	return lookupIndexed(get, names, func(i *projectTagIndex) map[string]int64 { return i.tagIDs }, func(missing []string) error {
+		return TagNotFoundError{ProjectID: projectID, Names: missing}
+	})
+}
+
+// GetTagNames returns the names of the tags of the project with the given IDs
+func (c *fsClient) GetTagNames(projectID int64, tagIDs []int64) ([]string, error) {
+	get := func(refresh bool) (*projectTagIndex, bool, error) { return c.getProjectTagIndex(projectID, refresh) }
+	return lookupIndexed(get, tagIDs, func(i *projectTagIndex) map[int64]string { return i.tagNames }, func(missing []int64) error {
+		return fmt.Errorf("tags %v not found in project %d", missing, projectID)
+	})
+}
+
+// Resources managing environment feature states, as reported by
+// ClaimFeatureState
+const (
+	featureStateManagerFeatureState = "a flagsmith_feature_state resource"
+	featureStateManagerFeature      = "the environment_values of a flagsmith_feature resource"
+)
+
+// ClaimFeatureState records that the feature state of the feature in the
+// environment is managed by manager, erroring if another manager already
+// claimed it
+func (c *fsClient) ClaimFeatureState(environmentKey string, featureID int64, manager string) error {
+	key := fmt.Sprintf("%s/%d", environmentKey, featureID)
+	current, loaded := c.featureStateManagers.LoadOrStore(key, manager)
+	if loaded && current.(string) != manager {
+		return fmt.Errorf("the feature state of feature %d in environment %q is already managed by %s", featureID, environmentKey, current)
+	}
+	return nil
+}
+
+// IsSensitiveEnvironmentFeature is IsSensitiveFeature for a feature of the
+// project of the given environment
+func (c *fsClient) IsSensitiveEnvironmentFeature(environmentKey string, featureID int64) (bool, error) {
+	if c.sensitiveFeatureTag == "" {
+		return false, nil
+	}
+	if c.sensitiveEnvironmentKeys != nil && !c.sensitiveEnvironmentKeys[environmentKey] {
+		return false, nil
+	}
+	environment, err := c.GetEnvironment(environmentKey)
+	if err != nil {
+		return false, err
+	}
+	return c.IsSensitiveFeature(environmentKey, environment.ProjectID, featureID)
+}
+
+// Get the feature segment that binds the given segment to a feature in an environment
+func (c *fsClient) GetFeatureSegmentBySegment(environmentID, featureID, segmentID int64) (*flagsmithapi.FeatureSegment, error) {
+	featureSegments, err := c.GetFeatureSegments(environmentID, featureID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range featureSegments {
+		if featureSegments[i].Segment != nil && *featureSegments[i].Segment == segmentID {
+			featureSegment := featureSegments[i]
+			return &featureSegment, nil
+		}
+	}
+	return nil, FeatureSegmentNotFoundError{environmentID: environmentID, featureID: featureID, segmentID: segmentID}
+}
+
+// Get the feature state(i.e: segment override) associated with a feature segment
+func (c *fsClient) GetFeatureSegmentFeatureState(environmentID, featureID, featureSegmentID int64) (*flagsmithapi.FeatureState, error) {
+	url := fmt.Sprintf("%s/features/featurestates/", c.baseURL)
+	result := struct {
+		Results []*flagsmithapi.FeatureState `json:"results"`
+	}{}
+	resp, err := c.rest.R().
+		SetQueryParams(map[string]string{
+			"environment":     strconv.FormatInt(environmentID, 10),
+			"feature":         strconv.FormatInt(featureID, 10),
+			"feature_segment": strconv.FormatInt(featureSegmentID, 10),
+		}).
+		SetResult(&result).
+		Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("flagsmithapi: Error getting segment override feature state: %s", resp)
+	}
+	for _, featureState := range result.Results {
+		if featureState.FeatureSegment != nil && *featureState.FeatureSegment == featureSegmentID {
+			return featureState, nil
+		}
+	}
+	return nil, FeatureSegmentNotFoundError{environmentID: environmentID, featureID: featureID}
+}
+
+// Get the segment override of a segment for a feature in an environment, with
+// its segment and priority loaded
+func (c *fsClient) GetSegmentOverride(environmentID, featureID, segmentID int64) (*flagsmithapi.FeatureState, error) {
+	featureSegment, err := c.GetFeatureSegmentBySegment(environmentID, featureID, segmentID)
+	if err != nil {
+		return nil, err
+	}
+	featureState, err := c.GetFeatureSegmentFeatureState(environmentID, featureID, *featureSegment.ID)
+	if err != nil {
+		return nil, err
+	}
+	featureState.Segment = featureSegment.Segment
+	featureState.SegmentPriority = featureSegment.Priority
+	return featureState, nil
+}
+
+// MultivariateFeatureStateValue is the percentage of the identities served a
+// multivariate option of the feature by a feature state
+type MultivariateFeatureStateValue struct {
+	ID                        *int64  `json:"id,omitempty"`
+	MultivariateFeatureOption int64   `json:"multivariate_feature_option"`
+	PercentageAllocation      float64 `json:"percentage_allocation"`
+}
+
+// FeatureStateDetails holds the fields of a feature state that are not part
+// of flagsmithapi.FeatureState
+type FeatureStateDetails struct {
+	UpdatedAt                      string                          `json:"updated_at"`
+	MultivariateFeatureStateValues []MultivariateFeatureStateValue `json:"multivariate_feature_state_values"`
+}
+
+// Get the fields of a feature state that are not part of flagsmithapi.FeatureState
+func (c *fsClient) GetFeatureStateDetails(featureStateID int64) (*FeatureStateDetails, error) {
+	url := fmt.Sprintf("%s/features/featurestates/%d/", c.baseURL, featureStateID)
+	details := FeatureStateDetails{}
+	resp, err := c.rest.R().SetResult(&details).Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("flagsmithapi: Error getting feature state: %s", resp)
+	}
+	return &details, nil
+}
+
+// Get the weights of the multivariate options of a feature state
+func (c *fsClient) GetFeatureStateMultivariateValues(featureStateID int64) ([]MultivariateFeatureStateValue, error) {
+	details, err := c.GetFeatureStateDetails(featureStateID)
+	if err != nil {
+		return nil, err
+	}
+	return details.MultivariateFeatureStateValues, nil
+}
+
+// Update the weights of the multivariate options of a feature state
+func (c *fsClient) UpdateFeatureStateMultivariateValues(featureState *flagsmithapi.FeatureState, values []MultivariateFeatureStateValue) error {
+	defer c.invalidateFeatureStates(featureState.EnvironmentKey)
+	url := fmt.Sprintf("%s/features/featurestates/%d/", c.baseURL, featureState.ID)
+	body := map[string]interface{}{"multivariate_feature_state_values": values}
+	resp, err := c.rest.R().SetBody(body).Patch(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error updating multivariate feature state values: %s", resp)
+	}
+	return nil
+}
+
+// Reorder the given feature segments(i.e: segment overrides) of a feature in a
+// single request. The first feature segment gets the highest priority(0).
+func (c *fsClient) ReorderFeatureSegments(featureSegmentIDs []int64) error {
+	defer c.invalidateFeatureStates("")
+	type featureSegmentPriority struct {
+		Priority int64 `json:"priority"`
+		ID       int64 `json:"id"`
+	}
+	body := make([]featureSegmentPriority, 0, len(featureSegmentIDs))
+	for priority, featureSegmentID := range featureSegmentIDs {
+		body = append(body, featureSegmentPriority{Priority: int64(priority), ID: featureSegmentID})
+	}
+	url := fmt.Sprintf("%s/features/feature-segments/update-priorities/", c.baseURL)
+	resp, err := c.rest.R().SetBody(body).Post(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error updating feature segment priorities: %s", resp)
+	}
+
+	return nil
+}
+
+type FeatureSegmentNotFoundError struct {
+	environmentID int64
+	featureID     int64
+	segmentID     int64
+}
+
+func (e FeatureSegmentNotFoundError) Error() string {
+	if e.segmentID == 0 {
+		return fmt.Sprintf("flagsmithapi: segment override for feature '%d' not found in environment '%d'", e.featureID, e.environmentID)
+	}
+	return fmt.Sprintf("flagsmithapi: segment override of segment '%d' for feature '%d' not found in environment '%d'", e.segmentID, e.featureID, e.environmentID)
+}
+
+type ChangeRequestApproval struct {
+	User       int64   `json:"user"`
+	ApprovedAt *string `json:"approved_at,omitempty"`
+}
+
+// ChangeRequestFeatureState is a feature state proposed by a change request,
+// going live at `live_from` if set
+type ChangeRequestFeatureState struct {
+	Feature           int64                           `json:"feature"`
+	Enabled           bool                            `json:"enabled"`
+	FeatureStateValue *flagsmithapi.FeatureStateValue `json:"feature_state_value"`
+	Environment       *int64                          `json:"environment,omitempty"`
+	LiveFrom          *string                         `json:"live_from,omitempty"`
+}
+
+type ChangeRequest struct {
+	ID            int64                       `json:"id,omitempty"`
+	Title         string                      `json:"title"`
+	Description   string                      `json:"description"`
+	FeatureStates []ChangeRequestFeatureState `json:"feature_states"`
+	Approvals     []ChangeRequestApproval     `json:"approvals"`
+	CommittedAt   *string                     `json:"committed_at,omitempty"`
+	DeletedAt     *string                     `json:"deleted_at,omitempty"`
+}
+
+// IsScheduled reports whether the change request has been committed and one of
+// its feature states goes live after now
+func (cr *ChangeRequest) IsScheduled(now time.Time) bool {
+	if cr.DeletedAt != nil || cr.CommittedAt == nil {
+		return false
+	}
+	for _, featureState := range cr.FeatureStates {
+		if featureState.LiveFrom == nil {
+			continue
+		}
+		liveFrom, err := time.Parse(time.RFC3339, *featureState.LiveFrom)
+		if err == nil && liveFrom.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Modes of writing feature states, directly or through a change request
+const (
+	ChangeRequestModeNever  = "never"
+	ChangeRequestModeAuto   = "auto"
+	ChangeRequestModeAlways = "always"
+)
+
+var changeRequestModes = []string{ChangeRequestModeNever, ChangeRequestModeAuto, ChangeRequestModeAlways}
+
+// UseChangeRequest reports whether a feature state of the environment must be
+// written through a change request in the given mode, the provider default
+// being used if mode is empty
+func (c *fsClient) UseChangeRequest(environmentKey string, mode string) (bool, error) {
+	if mode == "" {
+		mode = c.changeRequestMode
+	}
+	switch mode {
+	case ChangeRequestModeAlways:
+		return true, nil
+	case ChangeRequestModeAuto:
+		environment, err := c.GetEnvironment(environmentKey)
+		if err != nil {
+			return false, err
+		}
+		return environment.MinimumChangeRequestApprovals > 0, nil
+	}
+	return false, nil
+}
+
+// Statuses of a change request
+const (
+	ChangeRequestPendingApproval = "pending_approval"
+	ChangeRequestApproved        = "approved"
+	ChangeRequestCommitted       = "committed"
+	ChangeRequestDeleted         = "deleted"
+)
+
+// Status returns the status of the change request given the number of
+// approvals required by its environment
+func (cr *ChangeRequest) Status(minimumApprovals int64) string {
+	if cr.DeletedAt != nil {
+		return ChangeRequestDeleted
+	}
+	if cr.CommittedAt != nil {
+		return ChangeRequestCommitted
+	}
+	approvals := int64(0)
+	for _, approval := range cr.Approvals {
+		if approval.ApprovedAt != nil {
+			approvals++
+		}
+	}
+	if approvals >= minimumApprovals {
+		return ChangeRequestApproved
+	}
+	return ChangeRequestPendingApproval
+}
+
+// Open a change request for the feature states of an environment, instead of
+// updating them directly
+func (c *fsClient) CreateChangeRequest(environmentKey string, changeRequest *ChangeRequest) error {
+	defer c.invalidateFeatureStates(environmentKey)
+	url := fmt.Sprintf("%s/environments/%s/create-change-request/", c.baseURL, environmentKey)
+	if changeRequest.Approvals == nil {
+		changeRequest.Approvals = []ChangeRequestApproval{}
+	}
+	resp, err := c.rest.R().SetBody(changeRequest).SetResult(changeRequest).Post(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error creating change request: %s", resp)
+	}
+
+	return nil
+}
+
+func (c *fsClient) GetChangeRequest(changeRequestID int64) (*ChangeRequest, error) {
+	url := fmt.Sprintf("%s/features/workflows/change-requests/%d/", c.baseURL, changeRequestID)
+	changeRequest := ChangeRequest{}
+	resp, err := c.rest.R().SetResult(&changeRequest).Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		if resp.StatusCode() == http.StatusNotFound {
+			return nil, ChangeRequestNotFoundError{changeRequestID: changeRequestID}
+		}
+		return nil, fmt.Errorf("flagsmithapi: Error getting change request: %s", resp)
+	}
+
+	return &changeRequest, nil
+}
+
+// Commit a change request, making its feature states live(or scheduled if
+// they have a `live_from`)
+func (c *fsClient) CommitChangeRequest(changeRequestID int64) error {
+	// The environment of the change request is not known
+	defer c.invalidateFeatureStates("")
+	url := fmt.Sprintf("%s/features/workflows/change-requests/%d/commit/", c.baseURL, changeRequestID)
+	resp, err := c.rest.R().Post(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error committing change request: %s", resp)
+	}
+
+	return nil
+}
+
+func (c *fsClient) DeleteChangeRequest(changeRequestID int64) error {
+	defer c.invalidateFeatureStates("")
+	url := fmt.Sprintf("%s/features/workflows/change-requests/%d/", c.baseURL, changeRequestID)
+	resp, err := c.rest.R().Delete(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() && resp.StatusCode() != http.StatusNotFound {
+		return fmt.Errorf("flagsmithapi: Error deleting change request: %s", resp)
+	}
+
+	return nil
+}
+
+type ChangeRequestNotFoundError struct {
+	changeRequestID int64
+}
+
+func (e ChangeRequestNotFoundError) Error() string {
+	return fmt.Sprintf("flagsmithapi: change request '%d' not found", e.changeRequestID)
+}
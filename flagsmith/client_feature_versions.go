@@ -0,0 +1,122 @@
+package flagsmith
+
+import (
+	"fmt"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+)
+
+// FeatureVersionFeatureSegment binds a feature state of a feature version to a
+// segment
+type FeatureVersionFeatureSegment struct {
+	Segment  int64  `json:"segment"`
+	Priority *int64 `json:"priority,omitempty"`
+}
+
+// FeatureVersionFeatureState is a feature state created or updated by a
+// feature version, the environment default if FeatureSegment is nil
+type FeatureVersionFeatureState struct {
+	FeatureSegment                 *FeatureVersionFeatureSegment   `json:"feature_segment"`
+	Enabled                        bool                            `json:"enabled"`
+	FeatureStateValue              *flagsmithapi.FeatureStateValue `json:"feature_state_value"`
+	MultivariateFeatureStateValues []MultivariateFeatureStateValue `json:"multivariate_feature_state_values,omitempty"`
+}
+
+// FeatureVersion is a version of the feature states of a feature in an
+// environment using v2 feature versioning
+type FeatureVersion struct {
+	UUID                        string                       `json:"uuid,omitempty"`
+	FeatureStatesToCreate       []FeatureVersionFeatureState `json:"feature_states_to_create,omitempty"`
+	FeatureStatesToUpdate       []FeatureVersionFeatureState `json:"feature_states_to_update,omitempty"`
+	SegmentIDsToDeleteOverrides []int64                      `json:"segment_ids_to_delete_overrides,omitempty"`
+	Published                   bool                         `json:"published,omitempty"`
+}
+
+// IsV2Versioned reports whether the environment uses v2 feature versioning, in
+// which case its feature states can only be changed by publishing a new
+// feature version. The answer is cached for the lifetime of the provider.
+func (c *fsClient) IsV2Versioned(environmentKey string) (bool, error) {
+	if versioned, ok := c.versionedEnvironments.Load(environmentKey); ok {
+		return versioned.(bool), nil
+	}
+	url := fmt.Sprintf("%s/environments/%s/", c.baseURL, environmentKey)
+	result := struct {
+		UseV2FeatureVersioning bool `json:"use_v2_feature_versioning"`
+	}{}
+	resp, err := c.rest.R().SetResult(&result).Get(url)
+
+	if err != nil {
+		return false, err
+	}
+
+	if !resp.IsSuccess() {
+		return false, fmt.Errorf("flagsmithapi: Error getting environment: %s", resp)
+	}
+	c.versionedEnvironments.Store(environmentKey, result.UseV2FeatureVersioning)
+	return result.UseV2FeatureVersioning, nil
+}
+
+// Enable v2 feature versioning for the environment. Flagsmith migrates the
+// environment asynchronously, and versioning can not be disabled afterwards.
+func (c *fsClient) EnableV2Versioning(environmentKey string) error {
+	url := fmt.Sprintf("%s/environments/%s/enable-v2-versioning/", c.baseURL, environmentKey)
+	resp, err := c.rest.R().Post(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error enabling v2 versioning: %s", resp)
+	}
+	c.versionedEnvironments.Store(environmentKey, true)
+
+	return nil
+}
+
+// Create a feature version with the given changes and publish it right away.
+// The uuid of the published version is set on the given version.
+func (c *fsClient) PublishFeatureVersion(environmentID, featureID int64, version *FeatureVersion) error {
+	url := fmt.Sprintf("%s/environments/%d/features/%d/versions/", c.baseURL, environmentID, featureID)
+	resp, err := c.rest.R().SetBody(version).SetResult(version).Post(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error creating feature version: %s", resp)
+	}
+
+	url = fmt.Sprintf("%s/environments/%d/features/%d/versions/%s/publish/", c.baseURL, environmentID, featureID, version.UUID)
+	resp, err = c.rest.R().Post(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error publishing feature version: %s", resp)
+	}
+	version.Published = true
+
+	// Publishing a version replaces the feature states of the environment
+	c.invalidateFeatureStates("")
+	return nil
+}
+
+// NewFeatureVersionFeatureState returns the feature state of a feature version
+// matching the given feature state
+func NewFeatureVersionFeatureState(featureState *flagsmithapi.FeatureState) FeatureVersionFeatureState {
+	versionFeatureState := FeatureVersionFeatureState{
+		Enabled:           featureState.Enabled,
+		FeatureStateValue: featureState.FeatureStateValue,
+	}
+	if featureState.Segment != nil {
+		versionFeatureState.FeatureSegment = &FeatureVersionFeatureSegment{
+			Segment:  *featureState.Segment,
+			Priority: featureState.SegmentPriority,
+		}
+	}
+	return versionFeatureState
+}
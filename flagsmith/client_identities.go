@@ -0,0 +1,488 @@
+package flagsmith
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/go-resty/resty/v2"
+)
+
+// IdentityOverride is the feature state of a feature overridden for an
+// identity. ID is the feature state id for core identities and its uuid for
+// Edge identities.
+type IdentityOverride struct {
+	ID                string
+	Feature           int64
+	Enabled           bool
+	FeatureStateValue *flagsmithapi.FeatureStateValue
+}
+
+func (o *IdentityOverride) UnmarshalJSON(data []byte) error {
+	var obj struct {
+		ID                *int64          `json:"id"`
+		FeatureStateUUID  string          `json:"featurestate_uuid"`
+		Feature           int64           `json:"feature"`
+		Enabled           bool            `json:"enabled"`
+		FeatureStateValue json.RawMessage `json:"feature_state_value"`
+	}
+	err := json.Unmarshal(data, &obj)
+	if err != nil {
+		return err
+	}
+	o.ID = obj.FeatureStateUUID
+	if obj.ID != nil {
+		o.ID = strconv.FormatInt(*obj.ID, 10)
+	}
+	o.Feature = obj.Feature
+	o.Enabled = obj.Enabled
+	o.FeatureStateValue, err = parseRawFeatureStateValue(obj.FeatureStateValue)
+	return err
+}
+
+func (o *IdentityOverride) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Feature                        int64         `json:"feature"`
+		Enabled                        bool          `json:"enabled"`
+		FeatureStateValue              interface{}   `json:"feature_state_value"`
+		MultivariateFeatureStateValues []interface{} `json:"multivariate_feature_state_values"`
+	}{
+		Feature:                        o.Feature,
+		Enabled:                        o.Enabled,
+		FeatureStateValue:              rawFeatureStateValue(o.FeatureStateValue),
+		MultivariateFeatureStateValues: []interface{}{},
+	})
+}
+
+// parseRawFeatureStateValue converts the untyped value returned by the
+// identities APIs, a null value being an empty string
+func parseRawFeatureStateValue(raw json.RawMessage) (*flagsmithapi.FeatureStateValue, error) {
+	var value interface{}
+	if len(raw) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+	}
+	switch v := value.(type) {
+	case json.Number:
+		intValue, err := v.Int64()
+		if err != nil {
+			return nil, err
+		}
+		return &flagsmithapi.FeatureStateValue{Type: "int", IntegerValue: &intValue}, nil
+	case bool:
+		return &flagsmithapi.FeatureStateValue{Type: "bool", BooleanValue: &v}, nil
+	case string:
+		return &flagsmithapi.FeatureStateValue{Type: "unicode", StringValue: &v}, nil
+	}
+	emptyString := ""
+	return &flagsmithapi.FeatureStateValue{Type: "unicode", StringValue: &emptyString}, nil
+}
+
+// rawFeatureStateValue converts a typed value to the untyped value expected by
+// the identities APIs
+func rawFeatureStateValue(value *flagsmithapi.FeatureStateValue) interface{} {
+	if value == nil {
+		return nil
+	}
+	switch value.Type {
+	case "int":
+		return value.IntegerValue
+	case "bool":
+		return value.BooleanValue
+	}
+	return value.StringValue
+}
+
+// identitiesURL returns the url of the identities of the environment, using
+// the Edge identities API if edge is true
+func (c *fsClient) identitiesURL(environmentKey string, edge bool) string {
+	if edge {
+		return fmt.Sprintf("%s/environments/%s/edge-identities/", c.baseURL, environmentKey)
+	}
+	return fmt.Sprintf("%s/environments/%s/identities/", c.baseURL, environmentKey)
+}
+
+// identityOverridesURL returns the url of the feature states of an identity
+func (c *fsClient) identityOverridesURL(environmentKey, identityID string, edge bool) string {
+	if edge {
+		return fmt.Sprintf("%s%s/edge-featurestates/", c.identitiesURL(environmentKey, edge), identityID)
+	}
+	return fmt.Sprintf("%s%s/featurestates/", c.identitiesURL(environmentKey, edge), identityID)
+}
+
+// UsesEdgeIdentities reports whether the identities of the environment are
+// stored in Edge(i.e: the project is hosted on SaaS with Edge enabled)
+func (c *fsClient) UsesEdgeIdentities(environmentKey string) (bool, error) {
+	environment, err := c.GetEnvironment(environmentKey)
+	if err != nil {
+		return false, err
+	}
+	url := fmt.Sprintf("%s/projects/%d/", c.baseURL, environment.ProjectID)
+	result := struct {
+		UseEdgeIdentities bool `json:"use_edge_identities"`
+	}{}
+	resp, err := c.rest.R().SetResult(&result).Get(url)
+
+	if err != nil {
+		return false, err
+	}
+
+	if !resp.IsSuccess() {
+		return false, fmt.Errorf("flagsmithapi: Error getting project: %s", resp)
+	}
+	return result.UseEdgeIdentities, nil
+}
+
+// GetIdentityID returns the ID of the identity with the given identifier, which
+// is its uuid for Edge identities
+func (c *fsClient) GetIdentityID(environmentKey, identifier string, edge bool) (string, error) {
+	url := c.identitiesURL(environmentKey, edge)
+	result := struct {
+		Results []struct {
+			ID           *int64 `json:"id"`
+			IdentityUUID string `json:"identity_uuid"`
+			Identifier   string `json:"identifier"`
+		} `json:"results"`
+	}{}
+	resp, err := c.rest.R().SetQueryParam("q", identifier).SetResult(&result).Get(url)
+
+	if err != nil {
+		return "", err
+	}
+
+	if !resp.IsSuccess() {
+		return "", fmt.Errorf("flagsmithapi: Error getting identities: %s", resp)
+	}
+	// The search matches the identifiers starting with the query
+	for _, identity := range result.Results {
+		if identity.Identifier != identifier {
+			continue
+		}
+		if identity.ID != nil {
+			return strconv.FormatInt(*identity.ID, 10), nil
+		}
+		return identity.IdentityUUID, nil
+	}
+	return "", IdentityNotFoundError{environmentKey: environmentKey, identifier: identifier}
+}
+
+// Create an identity with the given identifier and return its ID, which is its
+// uuid for Edge identities
+func (c *fsClient) CreateIdentityByIdentifier(environmentKey, identifier string, edge bool) (string, error) {
+	url := c.identitiesURL(environmentKey, edge)
+	result := struct {
+		ID           *int64 `json:"id"`
+		IdentityUUID string `json:"identity_uuid"`
+	}{}
+	resp, err := c.rest.R().SetBody(map[string]string{"identifier": identifier}).SetResult(&result).Post(url)
+
+	if err != nil {
+		return "", err
+	}
+
+	if !resp.IsSuccess() {
+		return "", fmt.Errorf("flagsmithapi: Error creating identity: %s", resp)
+	}
+	if result.ID != nil {
+		return strconv.FormatInt(*result.ID, 10), nil
+	}
+	return result.IdentityUUID, nil
+}
+
+// GetIdentityOverride returns the override of the given feature for an identity
+func (c *fsClient) GetIdentityOverride(environmentKey, identityID string, featureID int64, edge bool) (*IdentityOverride, error) {
+	url := c.identityOverridesURL(environmentKey, identityID, edge)
+	req := c.rest.R().SetQueryParam("feature", strconv.FormatInt(featureID, 10))
+
+	var overrides []IdentityOverride
+	var resp *resty.Response
+	var err error
+	if edge {
+		resp, err = req.SetResult(&overrides).Get(url)
+	} else {
+		result := struct {
+			Results []IdentityOverride `json:"results"`
+		}{}
+		resp, err = req.SetResult(&result).Get(url)
+		overrides = result.Results
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		if resp.StatusCode() == http.StatusNotFound {
+			return nil, IdentityOverrideNotFoundError{environmentKey: environmentKey, identityID: identityID, featureID: featureID}
+		}
+		return nil, fmt.Errorf("flagsmithapi: Error getting identity feature states: %s", resp)
+	}
+	for i := range overrides {
+		if overrides[i].Feature == featureID {
+			return &overrides[i], nil
+		}
+	}
+	return nil, IdentityOverrideNotFoundError{environmentKey: environmentKey, identityID: identityID, featureID: featureID}
+}
+
+// Create an override of a feature for an identity. The ID of the created
+// override is set on the given override.
+func (c *fsClient) CreateIdentityOverride(environmentKey, identityID string, edge bool, override *IdentityOverride) error {
+	url := c.identityOverridesURL(environmentKey, identityID, edge)
+	result := IdentityOverride{}
+	resp, err := c.rest.R().SetBody(override).SetResult(&result).Post(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error creating identity override: %s", resp)
+	}
+	override.ID = result.ID
+	return nil
+}
+
+// Update an override of a feature for an identity
+func (c *fsClient) UpdateIdentityOverride(environmentKey, identityID string, edge bool, override *IdentityOverride) error {
+	url := c.identityOverridesURL(environmentKey, identityID, edge) + override.ID + "/"
+	resp, err := c.rest.R().SetBody(override).Put(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error updating identity override: %s", resp)
+	}
+	return nil
+}
+
+// Delete an override of a feature for an identity, the identity itself is kept
+func (c *fsClient) DeleteIdentityOverride(environmentKey, identityID string, edge bool, overrideID string) error {
+	url := c.identityOverridesURL(environmentKey, identityID, edge) + overrideID + "/"
+	resp, err := c.rest.R().Delete(url)
+
+	if err != nil {
+		return err
+	}
+
+	// The override may already be gone along with the identity
+	if !resp.IsSuccess() && resp.StatusCode() != http.StatusNotFound {
+		return fmt.Errorf("flagsmithapi: Error deleting identity override: %s", resp)
+	}
+	return nil
+}
+
+type IdentityNotFoundError struct {
+	environmentKey string
+	identifier     string
+}
+
+func (e IdentityNotFoundError) Error() string {
+	return fmt.Sprintf("flagsmithapi: identity '%s' not found in environment '%s'", e.identifier, e.environmentKey)
+}
+
+type IdentityOverrideNotFoundError struct {
+	environmentKey string
+	identityID     string
+	featureID      int64
+}
+
+func (e IdentityOverrideNotFoundError) Error() string {
+	return fmt.Sprintf("flagsmithapi: override of feature '%d' not found for identity '%s' in environment '%s'", e.featureID, e.identityID, e.environmentKey)
+}
+
+// parseRawTrait converts the untyped trait value returned by the Edge
+// identities API to a typed trait
+func parseRawTrait(key string, raw json.RawMessage) (flagsmithapi.Trait, error) {
+	trait := flagsmithapi.Trait{TraitKey: key}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return trait, err
+	}
+	switch v := value.(type) {
+	case json.Number:
+		if intValue, err := v.Int64(); err == nil {
+			integerValue := int(intValue)
+			trait.ValueType = "int"
+			trait.IntegerValue = &integerValue
+			return trait, nil
+		}
+		floatValue, err := v.Float64()
+		if err != nil {
+			return trait, err
+		}
+		trait.ValueType = "float"
+		trait.FloatValue = &floatValue
+	case bool:
+		trait.ValueType = "bool"
+		trait.BooleanValue = &v
+	case string:
+		trait.ValueType = "unicode"
+		trait.StringValue = &v
+	}
+	return trait, nil
+}
+
+// rawTraitValue converts a typed trait to the untyped value expected by the
+// Edge identities API
+func rawTraitValue(trait *flagsmithapi.Trait) interface{} {
+	switch trait.ValueType {
+	case "int":
+		return trait.IntegerValue
+	case "float":
+		return trait.FloatValue
+	case "bool":
+		return trait.BooleanValue
+	}
+	return trait.StringValue
+}
+
+// GetIdentityTraits returns the traits of an identity keyed by trait key
+func (c *fsClient) GetIdentityTraits(environmentKey, identityID string, edge bool) (map[string]flagsmithapi.Trait, error) {
+	traits := map[string]flagsmithapi.Trait{}
+	if !edge {
+		id, err := strconv.ParseInt(identityID, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		coreTraits, err := c.GetTraits(environmentKey, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, trait := range coreTraits {
+			traits[trait.TraitKey] = trait
+		}
+		return traits, nil
+	}
+
+	url := fmt.Sprintf("%s%s/list-traits/", c.identitiesURL(environmentKey, edge), identityID)
+	edgeTraits := []struct {
+		TraitKey   string          `json:"trait_key"`
+		TraitValue json.RawMessage `json:"trait_value"`
+	}{}
+	resp, err := c.rest.R().SetResult(&edgeTraits).Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("flagsmithapi: Error fetching traits: %s", resp)
+	}
+	for _, edgeTrait := range edgeTraits {
+		trait, err := parseRawTrait(edgeTrait.TraitKey, edgeTrait.TraitValue)
+		if err != nil {
+			return nil, err
+		}
+		traits[trait.TraitKey] = trait
+	}
+	return traits, nil
+}
+
+// SetIdentityTrait creates or updates a trait of an identity. Updating a trait
+// of a core identity requires the ID of the existing trait.
+func (c *fsClient) SetIdentityTrait(environmentKey, identityID string, edge bool, trait *flagsmithapi.Trait) error {
+	if !edge {
+		id, err := strconv.ParseInt(identityID, 10, 64)
+		if err != nil {
+			return err
+		}
+		if trait.ID != 0 {
+			return c.UpdateTrait(environmentKey, id, trait)
+		}
+		return c.CreateTrait(environmentKey, id, trait)
+	}
+	return c.updateEdgeTrait(environmentKey, identityID, trait.TraitKey, rawTraitValue(trait))
+}
+
+// DeleteIdentityTrait deletes a trait of an identity
+func (c *fsClient) DeleteIdentityTrait(environmentKey, identityID string, edge bool, trait *flagsmithapi.Trait) error {
+	if !edge {
+		id, err := strconv.ParseInt(identityID, 10, 64)
+		if err != nil {
+			return err
+		}
+		return c.DeleteTrait(environmentKey, id, trait.ID)
+	}
+	// Edge deletes the traits set to null
+	return c.updateEdgeTrait(environmentKey, identityID, trait.TraitKey, nil)
+}
+
+func (c *fsClient) updateEdgeTrait(environmentKey, identityID, traitKey string, value interface{}) error {
+	url := fmt.Sprintf("%s%s/update-traits/", c.identitiesURL(environmentKey, true), identityID)
+	resp, err := c.rest.R().SetBody(map[string]interface{}{"trait_key": traitKey, "trait_value": value}).Put(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error updating trait: %s", resp)
+	}
+	return nil
+}
+
+// Delete an identity along with its traits and overrides
+func (c *fsClient) DeleteIdentityByID(environmentKey, identityID string, edge bool) error {
+	url := fmt.Sprintf("%s%s/", c.identitiesURL(environmentKey, edge), identityID)
+	resp, err := c.rest.R().Delete(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() && resp.StatusCode() != http.StatusNotFound {
+		return fmt.Errorf("flagsmithapi: Error deleting identity: %s", resp)
+	}
+	return nil
+}
+
+// IdentityFlag is the feature state of a feature evaluated for an identity,
+// taking its overrides, its segments and the environment defaults into account
+type IdentityFlag struct {
+	Feature           flagsmithapi.Feature
+	Enabled           bool
+	FeatureStateValue *flagsmithapi.FeatureStateValue
+}
+
+func (f *IdentityFlag) UnmarshalJSON(data []byte) error {
+	var obj struct {
+		Feature           flagsmithapi.Feature `json:"feature"`
+		Enabled           bool                 `json:"enabled"`
+		FeatureStateValue json.RawMessage      `json:"feature_state_value"`
+	}
+	err := json.Unmarshal(data, &obj)
+	if err != nil {
+		return err
+	}
+	f.Feature = obj.Feature
+	f.Enabled = obj.Enabled
+	f.FeatureStateValue, err = parseRawFeatureStateValue(obj.FeatureStateValue)
+	return err
+}
+
+// GetIdentityFlags returns the flags of every feature evaluated for an identity
+func (c *fsClient) GetIdentityFlags(environmentKey, identityID string, edge bool) ([]IdentityFlag, error) {
+	url := fmt.Sprintf("%sall/", c.identityOverridesURL(environmentKey, identityID, edge))
+	flags := []IdentityFlag{}
+	resp, err := c.rest.R().SetResult(&flags).Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("flagsmithapi: Error fetching identity flags: %s", resp)
+	}
+	return flags, nil
+}
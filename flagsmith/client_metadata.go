@@ -0,0 +1,647 @@
+package flagsmith
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+)
+
+// Types of the values of metadata fields
+const (
+	MetadataFieldTypeInt       = "int"
+	MetadataFieldTypeStr       = "str"
+	MetadataFieldTypeBool      = "bool"
+	MetadataFieldTypeURL       = "url"
+	MetadataFieldTypeMultiline = "multiline_str"
+)
+
+var metadataFieldTypes = []string{MetadataFieldTypeInt, MetadataFieldTypeStr, MetadataFieldTypeBool, MetadataFieldTypeURL, MetadataFieldTypeMultiline}
+
+// Models metadata fields can be attached to
+const (
+	MetadataModelFeature     = "feature"
+	MetadataModelSegment     = "segment"
+	MetadataModelEnvironment = "environment"
+)
+
+var metadataModels = []string{MetadataModelFeature, MetadataModelSegment, MetadataModelEnvironment}
+
+// MetadataField is a field of metadata defined by an organisation
+type MetadataField struct {
+	ID           *int64 `json:"id,omitempty"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Description  string `json:"description"`
+	Organisation int64  `json:"organisation"`
+}
+
+// MetadataModelFieldRequirement makes a metadata model field required for the
+// objects of a project(or organisation), object being its ID
+type MetadataModelFieldRequirement struct {
+	ContentType int64 `json:"content_type"`
+	ObjectID    int64 `json:"object_id"`
+}
+
+// MetadataModelField attaches a metadata field to a model, identified by its
+// content type
+type MetadataModelField struct {
+	ID            *int64                          `json:"id,omitempty"`
+	Field         int64                           `json:"field"`
+	ContentType   int64                           `json:"content_type"`
+	IsRequiredFor []MetadataModelFieldRequirement `json:"is_required_for"`
+}
+
+// Metadata is the value of a metadata model field for a feature, segment or
+// environment
+type Metadata struct {
+	ModelField int64  `json:"model_field"`
+	FieldData  string `json:"field_data"`
+}
+
+type MetadataFieldNotFoundError struct {
+	fieldID int64
+}
+
+func (e MetadataFieldNotFoundError) Error() string {
+	return fmt.Sprintf("flagsmithapi: metadata field '%d' not found", e.fieldID)
+}
+
+type MetadataModelFieldNotFoundError struct {
+	modelFieldID int64
+}
+
+func (e MetadataModelFieldNotFoundError) Error() string {
+	return fmt.Sprintf("flagsmithapi: metadata model field '%d' not found", e.modelFieldID)
+}
+
+func (c *fsClient) GetMetadataField(fieldID int64) (*MetadataField, error) {
+	url := fmt.Sprintf("%s/metadata/fields/%d/", c.baseURL, fieldID)
+	field := MetadataField{}
+	resp, err := c.rest.R().SetResult(&field).Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		if resp.StatusCode() == http.StatusNotFound {
+			return nil, MetadataFieldNotFoundError{fieldID: fieldID}
+		}
+		return nil, fmt.Errorf("flagsmithapi: Error getting metadata field: %s", resp)
+	}
+	return &field, nil
+}
+
+// Get all the metadata fields of an organisation
+func (c *fsClient) GetOrganisationMetadataFields(orgID int64) ([]MetadataField, error) {
+	url := fmt.Sprintf("%s/metadata/fields/", c.baseURL)
+	fields := []MetadataField{}
+	resp, err := c.rest.R().SetQueryParam("organisation", strconv.FormatInt(orgID, 10)).SetResult(&fields).Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("flagsmithapi: Error getting metadata fields: %s", resp)
+	}
+	return fields, nil
+}
+
+func (c *fsClient) CreateMetadataField(field *MetadataField) error {
+	defer c.organisationMetadata.Delete(field.Organisation)
+	url := fmt.Sprintf("%s/metadata/fields/", c.baseURL)
+	resp, err := c.rest.R().SetBody(field).SetResult(field).Post(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error creating metadata field: %s", resp)
+	}
+	return nil
+}
+
+func (c *fsClient) UpdateMetadataField(field *MetadataField) error {
+	defer c.organisationMetadata.Delete(field.Organisation)
+	url := fmt.Sprintf("%s/metadata/fields/%d/", c.baseURL, *field.ID)
+	resp, err := c.rest.R().SetBody(field).SetResult(field).Put(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error updating metadata field: %s", resp)
+	}
+	return nil
+}
+
+func (c *fsClient) DeleteMetadataField(orgID, fieldID int64) error {
+	defer c.organisationMetadata.Delete(orgID)
+	url := fmt.Sprintf("%s/metadata/fields/%d/", c.baseURL, fieldID)
+	resp, err := c.rest.R().Delete(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() && resp.StatusCode() != http.StatusNotFound {
+		return fmt.Errorf("flagsmithapi: Error deleting metadata field: %s", resp)
+	}
+	return nil
+}
+
+func (c *fsClient) metadataModelFieldsURL(orgID int64) string {
+	return fmt.Sprintf("%s/organisations/%d/metadata-model-fields/", c.baseURL, orgID)
+}
+
+func (c *fsClient) GetMetadataModelField(orgID, modelFieldID int64) (*MetadataModelField, error) {
+	url := fmt.Sprintf("%s%d/", c.metadataModelFieldsURL(orgID), modelFieldID)
+	modelField := MetadataModelField{}
+	resp, err := c.rest.R().SetResult(&modelField).Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		if resp.StatusCode() == http.StatusNotFound {
+			return nil, MetadataModelFieldNotFoundError{modelFieldID: modelFieldID}
+		}
+		return nil, fmt.Errorf("flagsmithapi: Error getting metadata model field: %s", resp)
+	}
+	return &modelField, nil
+}
+
+// Get all the metadata model fields of an organisation
+func (c *fsClient) GetOrganisationMetadataModelFields(orgID int64) ([]MetadataModelField, error) {
+	modelFields := []MetadataModelField{}
+	resp, err := c.rest.R().SetResult(&modelFields).Get(c.metadataModelFieldsURL(orgID))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("flagsmithapi: Error getting metadata model fields: %s", resp)
+	}
+	return modelFields, nil
+}
+
+func (c *fsClient) CreateMetadataModelField(orgID int64, modelField *MetadataModelField) error {
+	defer c.organisationMetadata.Delete(orgID)
+	resp, err := c.rest.R().SetBody(modelField).SetResult(modelField).Post(c.metadataModelFieldsURL(orgID))
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error creating metadata model field: %s", resp)
+	}
+	return nil
+}
+
+func (c *fsClient) UpdateMetadataModelField(orgID int64, modelField *MetadataModelField) error {
+	defer c.organisationMetadata.Delete(orgID)
+	url := fmt.Sprintf("%s%d/", c.metadataModelFieldsURL(orgID), *modelField.ID)
+	resp, err := c.rest.R().SetBody(modelField).SetResult(modelField).Put(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error updating metadata model field: %s", resp)
+	}
+	return nil
+}
+
+func (c *fsClient) DeleteMetadataModelField(orgID, modelFieldID int64) error {
+	defer c.organisationMetadata.Delete(orgID)
+	url := fmt.Sprintf("%s%d/", c.metadataModelFieldsURL(orgID), modelFieldID)
+	resp, err := c.rest.R().Delete(url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() && resp.StatusCode() != http.StatusNotFound {
+		return fmt.Errorf("flagsmithapi: Error deleting metadata model field: %s", resp)
+	}
+	return nil
+}
+
+// getContentTypes returns the IDs of the content types listed by an endpoint
+// of the metadata model fields, keyed by model
+func (c *fsClient) getContentTypes(url string, queryParams map[string]string) (map[string]int64, error) {
+	contentTypes := []struct {
+		ID    int64  `json:"id"`
+		Model string `json:"model"`
+	}{}
+	resp, err := c.rest.R().SetQueryParams(queryParams).SetResult(&contentTypes).Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("flagsmithapi: Error getting content types: %s", resp)
+	}
+	ids := map[string]int64{}
+	for _, contentType := range contentTypes {
+		ids[contentType.Model] = contentType.ID
+	}
+	return ids, nil
+}
+
+// organisationMetadata indexes the metadata fields of an organisation by the
+// models they are attached to
+type organisationMetadata struct {
+	// contentTypes holds the content type IDs of the models, and of the
+	// projects the fields are required for
+	contentTypes map[string]int64
+
+	// modelFields holds the metadata model fields of each model, keyed by
+	// field name
+	modelFields map[string]map[string]OrganisationMetadataField
+}
+
+// OrganisationMetadataField is a metadata field as attached to a model
+type OrganisationMetadataField struct {
+	ModelFieldID int64
+	Name         string
+	Type         string
+
+	// RequiredFor holds the IDs of the projects the field is required for
+	RequiredFor []int64
+}
+
+// IsRequiredFor reports whether the field is required for the objects of the project
+func (f OrganisationMetadataField) IsRequiredFor(projectID int64) bool {
+	for _, requiredFor := range f.RequiredFor {
+		if requiredFor == projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// getOrganisationMetadata returns the metadata fields of an organisation,
+// listed once for the lifetime of the provider unless refresh is set or the
+// fields are changed through the provider. The second return value is true
+// if they were just listed.
+func (c *fsClient) getOrganisationMetadata(orgID int64, refresh bool) (*organisationMetadata, bool, error) {
+	if metadata, ok := c.organisationMetadata.Load(orgID); ok && !refresh {
+		return metadata.(*organisationMetadata), false, nil
+	}
+	contentTypes, err := c.getContentTypes(c.metadataModelFieldsURL(orgID)+"supported-content-types/", nil)
+	if err != nil {
+		return nil, false, err
+	}
+	requiredForContentTypes, err := c.getContentTypes(c.metadataModelFieldsURL(orgID)+"supported-required-for-models/", map[string]string{"model_name": MetadataModelFeature})
+	if err != nil {
+		return nil, false, err
+	}
+	contentTypes["project"] = requiredForContentTypes["project"]
+
+	fields, err := c.GetOrganisationMetadataFields(orgID)
+	if err != nil {
+		return nil, false, err
+	}
+	modelFields, err := c.GetOrganisationMetadataModelFields(orgID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	fieldsByID := map[int64]MetadataField{}
+	for _, field := range fields {
+		if field.ID != nil {
+			fieldsByID[*field.ID] = field
+		}
+	}
+	metadata := &organisationMetadata{contentTypes: contentTypes, modelFields: map[string]map[string]OrganisationMetadataField{}}
+	for model, contentType := range contentTypes {
+		metadata.modelFields[model] = map[string]OrganisationMetadataField{}
+		for _, modelField := range modelFields {
+			field, ok := fieldsByID[modelField.Field]
+			if modelField.ContentType != contentType || modelField.ID == nil || !ok {
+				continue
+			}
+			requiredFor := []int64{}
+			for _, requirement := range modelField.IsRequiredFor {
+				if requirement.ContentType == contentTypes["project"] {
+					requiredFor = append(requiredFor, requirement.ObjectID)
+				}
+			}
+			metadata.modelFields[model][field.Name] = OrganisationMetadataField{
+				ModelFieldID: *modelField.ID,
+				Name:         field.Name,
+				Type:         field.Type,
+				RequiredFor:  requiredFor,
+			}
+		}
+	}
+	c.organisationMetadata.Store(orgID, metadata)
+	return metadata, true, nil
+}
+
+// GetMetadataContentType returns the ID of the content type of a model
+func (c *fsClient) GetMetadataContentType(orgID int64, model string) (int64, error) {
+	get := func(refresh bool) (*organisationMetadata, bool, error) {
+		return c.getOrganisationMetadata(orgID, refresh)
+	}
+	contentTypes, err := lookupIndexed(get, []string{model}, func(m *organisationMetadata) map[string]int64 { return m.contentTypes }, func(missing []string) error {
+		return fmt.Errorf("metadata is not supported on %v", missing)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return contentTypes[0], nil
+}
+
+// GetMetadataModel returns the model of a content type
+func (c *fsClient) GetMetadataModel(orgID int64, contentType int64) (string, error) {
+	get := func(refresh bool) (*organisationMetadata, bool, error) {
+		return c.getOrganisationMetadata(orgID, refresh)
+	}
+	index := func(m *organisationMetadata) map[int64]string {
+		models := map[int64]string{}
+		for _, model := range metadataModels {
+			if id, ok := m.contentTypes[model]; ok {
+				models[id] = model
+			}
+		}
+		return models
+	}
+	models, err := lookupIndexed(get, []int64{contentType}, index, func(missing []int64) error {
+		return fmt.Errorf("metadata is not supported on content types %v", missing)
+	})
+	if err != nil {
+		return "", err
+	}
+	return models[0], nil
+}
+
+// MetadataFieldNotAttachedError is returned when metadata fields are looked up
+// by a name no field attached to the model has
+type MetadataFieldNotAttachedError struct {
+	Model string
+	Names []string
+}
+
+func (e MetadataFieldNotAttachedError) Error() string {
+	return fmt.Sprintf("metadata fields %v not found or not attached to %s", e.Names, e.Model)
+}
+
+// GetMetadataFields returns the metadata fields attached to the model with
+// the given names
+func (c *fsClient) GetMetadataFields(orgID int64, model string, names []string) ([]OrganisationMetadataField, error) {
+	get := func(refresh bool) (*organisationMetadata, bool, error) {
+		return c.getOrganisationMetadata(orgID, refresh)
+	}
+	return lookupIndexed(get, names, func(m *organisationMetadata) map[string]OrganisationMetadataField { return m.modelFields[model] }, func(missing []string) error {
+		return MetadataFieldNotAttachedError{Model: model, Names: missing}
+	})
+}
+
+// GetMetadataFieldsByModelFieldID returns the metadata fields attached to the
+// model with the given metadata model field IDs
+func (c *fsClient) GetMetadataFieldsByModelFieldID(orgID int64, model string, modelFieldIDs []int64) ([]OrganisationMetadataField, error) {
+	get := func(refresh bool) (*organisationMetadata, bool, error) {
+		return c.getOrganisationMetadata(orgID, refresh)
+	}
+	index := func(m *organisationMetadata) map[int64]OrganisationMetadataField {
+		fields := map[int64]OrganisationMetadataField{}
+		for _, field := range m.modelFields[model] {
+			fields[field.ModelFieldID] = field
+		}
+		return fields
+	}
+	return lookupIndexed(get, modelFieldIDs, index, func(missing []int64) error {
+		return fmt.Errorf("metadata model fields %v not found", missing)
+	})
+}
+
+// GetRequiredMetadataFields returns the names of the metadata fields required
+// for the objects of the model in the project
+func (c *fsClient) GetRequiredMetadataFields(orgID int64, model string, projectID int64) ([]string, error) {
+	metadata, _, err := c.getOrganisationMetadata(orgID, false)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for name, field := range metadata.modelFields[model] {
+		if field.IsRequiredFor(projectID) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ValidateMetadataValue errors if the value does not suit the type of the
+// metadata field
+func ValidateMetadataValue(fieldType, value string) error {
+	switch fieldType {
+	case MetadataFieldTypeInt:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+	case MetadataFieldTypeBool:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("%q is not a boolean, use \"true\" or \"false\"", value)
+		}
+	case MetadataFieldTypeURL:
+		parsed, err := url.ParseRequestURI(value)
+		if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return fmt.Errorf("%q is not an http(s) URL", value)
+		}
+	}
+	return nil
+}
+
+// getMetadata returns the metadata of the object at the given url
+func (c *fsClient) getMetadata(url string, what string) ([]Metadata, error) {
+	result := struct {
+		Metadata []Metadata `json:"metadata"`
+	}{}
+	resp, err := c.rest.R().SetResult(&result).Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("flagsmithapi: Error getting %s metadata: %s", what, resp)
+	}
+	if result.Metadata == nil {
+		return []Metadata{}, nil
+	}
+	return result.Metadata, nil
+}
+
+// saveWithMetadata creates or updates the object at the given url along with
+// its metadata, which the objects of the api client do not carry
+func (c *fsClient) saveWithMetadata(method, url string, object interface{}, metadata []Metadata, what string) error {
+	encoded, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{}
+	if err := json.Unmarshal(encoded, &body); err != nil {
+		return err
+	}
+	body["metadata"] = metadata
+	resp, err := c.rest.R().SetBody(body).SetResult(object).Execute(method, url)
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("flagsmithapi: Error saving %s: %s", what, resp)
+	}
+	return nil
+}
+
+// keptMetadata returns the metadata of the object at the given url, to be
+// sent back along with an update of the object since Flagsmith replaces the
+// metadata on updates. It is nil if the object has none.
+func (c *fsClient) keptMetadata(url string, what string) ([]Metadata, error) {
+	metadata, err := c.getMetadata(url, what)
+	if err != nil || len(metadata) == 0 {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func (c *fsClient) featureURL(projectID, featureID int64) string {
+	return fmt.Sprintf("%s/projects/%d/features/%d/", c.baseURL, projectID, featureID)
+}
+
+// Get the metadata of a feature
+func (c *fsClient) GetFeatureMetadata(projectID, featureID int64) ([]Metadata, error) {
+	return c.getMetadata(c.featureURL(projectID, featureID), "feature")
+}
+
+// Create a feature along with its metadata, if not nil
+func (c *fsClient) CreateFeatureWithMetadata(feature *flagsmithapi.Feature, metadata []Metadata) error {
+	if metadata == nil {
+		return c.CreateFeature(feature)
+	}
+	if feature.ProjectID == nil {
+		project, err := c.GetProject(feature.ProjectUUID)
+		if err != nil {
+			return err
+		}
+		feature.ProjectID = &project.ID
+	}
+	url := fmt.Sprintf("%s/projects/%d/features/", c.baseURL, *feature.ProjectID)
+	return c.saveWithMetadata(http.MethodPost, url, feature, metadata, "feature")
+}
+
+// Update a feature along with its metadata, which is kept as is if nil
+func (c *fsClient) UpdateFeatureWithMetadata(feature *flagsmithapi.Feature, metadata []Metadata) error {
+	url := c.featureURL(*feature.ProjectID, *feature.ID)
+	if metadata == nil {
+		kept, err := c.keptMetadata(url, "feature")
+		if err != nil {
+			return err
+		}
+		if kept == nil {
+			return c.UpdateFeature(feature)
+		}
+		metadata = kept
+	}
+	return c.saveWithMetadata(http.MethodPut, url, feature, metadata, "feature")
+}
+
+func (c *fsClient) segmentURL(projectID, segmentID int64) string {
+	return fmt.Sprintf("%s/projects/%d/segments/%d/", c.baseURL, projectID, segmentID)
+}
+
+// Get the metadata of a segment
+func (c *fsClient) GetSegmentMetadata(projectID, segmentID int64) ([]Metadata, error) {
+	return c.getMetadata(c.segmentURL(projectID, segmentID), "segment")
+}
+
+// Create a segment along with its metadata, if not nil
+func (c *fsClient) CreateSegmentWithMetadata(segment *flagsmithapi.Segment, metadata []Metadata) error {
+	if metadata == nil {
+		return c.CreateSegment(segment)
+	}
+	if segment.ProjectID == nil {
+		project, err := c.GetProject(segment.ProjectUUID)
+		if err != nil {
+			return err
+		}
+		segment.ProjectID = &project.ID
+	}
+	url := fmt.Sprintf("%s/projects/%d/segments/", c.baseURL, *segment.ProjectID)
+	return c.saveWithMetadata(http.MethodPost, url, segment, metadata, "segment")
+}
+
+// Update a segment along with its metadata, which is kept as is if nil
+func (c *fsClient) UpdateSegmentWithMetadata(segment *flagsmithapi.Segment, metadata []Metadata) error {
+	if segment.ProjectID == nil {
+		project, err := c.GetProject(segment.ProjectUUID)
+		if err != nil {
+			return err
+		}
+		segment.ProjectID = &project.ID
+	}
+	url := c.segmentURL(*segment.ProjectID, *segment.ID)
+	if metadata == nil {
+		kept, err := c.keptMetadata(url, "segment")
+		if err != nil {
+			return err
+		}
+		if kept == nil {
+			return c.UpdateSegment(segment)
+		}
+		metadata = kept
+	}
+	return c.saveWithMetadata(http.MethodPut, url, segment, metadata, "segment")
+}
+
+func (c *fsClient) environmentURL(environmentKey string) string {
+	return fmt.Sprintf("%s/environments/%s/", c.baseURL, environmentKey)
+}
+
+// Get the metadata of an environment
+func (c *fsClient) GetEnvironmentMetadata(environmentKey string) ([]Metadata, error) {
+	return c.getMetadata(c.environmentURL(environmentKey), "environment")
+}
+
+// Create an environment along with its metadata, if not nil
+func (c *fsClient) CreateEnvironmentWithMetadata(environment *flagsmithapi.Environment, metadata []Metadata) error {
+	if metadata == nil {
+		return c.CreateEnvironment(environment)
+	}
+	url := fmt.Sprintf("%s/environments/", c.baseURL)
+	return c.saveWithMetadata(http.MethodPost, url, environment, metadata, "environment")
+}
+
+// Update an environment along with its metadata, which is kept as is if nil
+func (c *fsClient) UpdateEnvironmentWithMetadata(environment *flagsmithapi.Environment, metadata []Metadata) error {
+	url := c.environmentURL(environment.APIKey)
+	if metadata == nil {
+		kept, err := c.keptMetadata(url, "environment")
+		if err != nil {
+			return err
+		}
+		if kept == nil {
+			return c.UpdateEnvironment(environment)
+		}
+		metadata = kept
+	}
+	return c.saveWithMetadata(http.MethodPut, url, environment, metadata, "environment")
+}
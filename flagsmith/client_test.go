@@ -0,0 +1,824 @@
+package flagsmith
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/stretchr/testify/assert"
+)
+
+const getFeatureSegmentsResponseJson = `
+{
+  "count": 2,
+  "next": null,
+  "previous": null,
+  "results": [
+    {"id": 10, "uuid": "ab45e8a5-2cb9-4a46-a4ab-2d03b2a4d0d4", "segment": 100, "priority": 0, "environment": 1, "feature": 1},
+    {"id": 11, "uuid": "4b4e0a43-06a6-4f4b-9f8f-3b0c3f6f3d9e", "segment": 101, "priority": 1, "environment": 1, "feature": 1}
+  ]
+}
+`
+
+const getFeatureSegmentFeatureStatesResponseJson = `
+{
+  "count": 1,
+  "next": null,
+  "previous": null,
+  "results": [
+    {
+      "id": 5,
+      "uuid": "1a1f9371-6181-4035-93f5-09bd291b7d5e",
+      "feature_state_value": {"type": "unicode", "string_value": "override", "integer_value": null, "boolean_value": null},
+      "enabled": true,
+      "feature": 1,
+      "environment": 1,
+      "feature_segment": 11
+    }
+  ]
+}
+`
+
+func TestGetFeatureSegmentBySegment(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/features/feature-segments/", req.URL.Path)
+		assert.Equal(t, "1", req.URL.Query().Get("environment"))
+		assert.Equal(t, "1", req.URL.Query().Get("feature"))
+		assert.Equal(t, "Api-Key master_api_key", req.Header.Get("Authorization"))
+
+		rw.Header().Set("Content-Type", "application/json")
+		_, err := rw.Write([]byte(getFeatureSegmentsResponseJson))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+
+	// When
+	featureSegment, err := client.GetFeatureSegmentBySegment(1, 1, 101)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), *featureSegment.ID)
+	assert.Equal(t, int64(1), *featureSegment.Priority)
+
+	// When
+	_, err = client.GetFeatureSegmentBySegment(1, 1, 102)
+
+	// Then
+	assert.IsType(t, FeatureSegmentNotFoundError{}, err)
+}
+
+func TestGetFeatureSegmentFeatureState(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/features/featurestates/", req.URL.Path)
+		assert.Equal(t, "11", req.URL.Query().Get("feature_segment"))
+
+		rw.Header().Set("Content-Type", "application/json")
+		_, err := rw.Write([]byte(getFeatureSegmentFeatureStatesResponseJson))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+
+	// When
+	featureState, err := client.GetFeatureSegmentFeatureState(1, 1, 11)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, "1a1f9371-6181-4035-93f5-09bd291b7d5e", featureState.UUID)
+	assert.Equal(t, int64(11), *featureState.FeatureSegment)
+	assert.Equal(t, "override", *featureState.FeatureStateValue.StringValue)
+}
+
+func TestGetEnvironmentFeatureStatesFollowsPages(t *testing.T) {
+	// Given
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/environments/env_key/featurestates/", req.URL.Path)
+
+		rw.Header().Set("Content-Type", "application/json")
+		body := `{"next": "` + serverURL + `/environments/env_key/featurestates/?page=2", "results": [
+			{"id": 1, "feature_state_value": "one", "enabled": true, "feature": 1, "environment": 1}
+		]}`
+		if req.URL.Query().Get("page") == "2" {
+			body = `{"next": null, "results": [
+				{"id": 2, "feature_state_value": 2, "enabled": false, "feature": 2, "environment": 1}
+			]}`
+		}
+		_, err := rw.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	serverURL = server.URL
+	client := newFSClient("master_api_key", server.URL)
+
+	// When
+	featureStates, err := client.GetEnvironmentFeatureStates("env_key")
+
+	// Then
+	assert.NoError(t, err)
+	assert.Len(t, featureStates, 2)
+	assert.Equal(t, "one", *featureStates[0].FeatureStateValue.StringValue)
+	assert.Equal(t, int64(2), *featureStates[1].FeatureStateValue.IntegerValue)
+	assert.Equal(t, "env_key", featureStates[1].EnvironmentKey)
+}
+
+func TestCreateChangeRequest(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/environments/env_key/create-change-request/", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+			"title": "title",
+			"description": "description",
+			"feature_states": [{"feature_state_value": {"type": "bool", "string_value": null, "integer_value": null, "boolean_value": true}, "enabled": true, "feature": 1, "environment": 1}],
+			"approvals": []
+		}`, string(body))
+
+		rw.Header().Set("Content-Type", "application/json")
+		_, err = rw.Write([]byte(`{"id": 7, "title": "title", "description": "description", "feature_states": [], "approvals": [{"user": 1, "approved_at": null}], "committed_at": null}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+	environmentID := int64(1)
+	value := true
+	changeRequest := ChangeRequest{
+		Title:       "title",
+		Description: "description",
+		FeatureStates: []ChangeRequestFeatureState{{
+			Enabled:           true,
+			FeatureStateValue: &flagsmithapi.FeatureStateValue{Type: "bool", BooleanValue: &value},
+			Feature:           1,
+			Environment:       &environmentID,
+		}},
+	}
+
+	// When
+	err := client.CreateChangeRequest("env_key", &changeRequest)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), changeRequest.ID)
+	assert.Equal(t, ChangeRequestPendingApproval, changeRequest.Status(1))
+	assert.Equal(t, ChangeRequestApproved, changeRequest.Status(0))
+}
+
+func TestChangeRequestStatus(t *testing.T) {
+	// Given
+	approvedAt := "2024-01-01T00:00:00Z"
+	approved := ChangeRequest{Approvals: []ChangeRequestApproval{{User: 1, ApprovedAt: &approvedAt}, {User: 2}}}
+	committed := ChangeRequest{CommittedAt: &approvedAt}
+	deleted := ChangeRequest{DeletedAt: &approvedAt}
+
+	// Then
+	assert.Equal(t, ChangeRequestApproved, approved.Status(1))
+	assert.Equal(t, ChangeRequestPendingApproval, approved.Status(2))
+	assert.Equal(t, ChangeRequestCommitted, committed.Status(1))
+	assert.Equal(t, ChangeRequestDeleted, deleted.Status(1))
+}
+
+func TestChangeRequestIsScheduled(t *testing.T) {
+	// Given
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	committedAt := "2023-12-31T00:00:00Z"
+	future := "2024-01-02T00:00:00Z"
+	past := "2023-12-31T12:00:00Z"
+	scheduled := ChangeRequest{CommittedAt: &committedAt, FeatureStates: []ChangeRequestFeatureState{{LiveFrom: &future}}}
+	live := ChangeRequest{CommittedAt: &committedAt, FeatureStates: []ChangeRequestFeatureState{{LiveFrom: &past}}}
+	notCommitted := ChangeRequest{FeatureStates: []ChangeRequestFeatureState{{LiveFrom: &future}}}
+	cancelled := ChangeRequest{CommittedAt: &committedAt, DeletedAt: &committedAt, FeatureStates: []ChangeRequestFeatureState{{LiveFrom: &future}}}
+
+	// Then
+	assert.True(t, scheduled.IsScheduled(now))
+	assert.False(t, live.IsScheduled(now))
+	assert.False(t, notCommitted.IsScheduled(now))
+	assert.False(t, cancelled.IsScheduled(now))
+}
+
+func TestPublishFeatureVersion(t *testing.T) {
+	// Given
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		paths = append(paths, req.URL.Path)
+		rw.Header().Set("Content-Type", "application/json")
+		if req.URL.Path == "/environments/1/features/2/versions/" {
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{
+				"feature_states_to_update": [{"feature_segment": {"segment": 3, "priority": 0}, "enabled": true, "feature_state_value": {"type": "unicode", "string_value": "value", "integer_value": null, "boolean_value": null}}]
+			}`, string(body))
+			_, err = rw.Write([]byte(`{"uuid": "version-uuid", "published": false}`))
+			assert.NoError(t, err)
+			return
+		}
+		_, err := rw.Write([]byte(`{}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+	segment := int64(3)
+	priority := int64(0)
+	value := "value"
+	version := FeatureVersion{
+		FeatureStatesToUpdate: []FeatureVersionFeatureState{NewFeatureVersionFeatureState(&flagsmithapi.FeatureState{
+			Enabled:           true,
+			FeatureStateValue: &flagsmithapi.FeatureStateValue{Type: "unicode", StringValue: &value},
+			Segment:           &segment,
+			SegmentPriority:   &priority,
+		})},
+	}
+
+	// When
+	err := client.PublishFeatureVersion(1, 2, &version)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, "version-uuid", version.UUID)
+	assert.True(t, version.Published)
+	assert.Equal(t, []string{
+		"/environments/1/features/2/versions/",
+		"/environments/1/features/2/versions/version-uuid/publish/",
+	}, paths)
+}
+
+func TestIsV2VersionedIsCached(t *testing.T) {
+	// Given
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		assert.Equal(t, "/environments/env_key/", req.URL.Path)
+		rw.Header().Set("Content-Type", "application/json")
+		_, err := rw.Write([]byte(`{"id": 1, "api_key": "env_key", "use_v2_feature_versioning": true}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+
+	// When
+	first, err := client.IsV2Versioned("env_key")
+	assert.NoError(t, err)
+	second, err := client.IsV2Versioned("env_key")
+
+	// Then
+	assert.NoError(t, err)
+	assert.True(t, first)
+	assert.True(t, second)
+	assert.Equal(t, 1, requests)
+}
+
+func TestIsSensitiveFeature(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		var err error
+		switch req.URL.Path {
+		case "/projects/1/tags/":
+			_, err = rw.Write([]byte(`[{"id": 7, "label": "other"}, {"id": 8, "label": "secret"}]`))
+		case "/projects/1/features/":
+			_, err = rw.Write([]byte(`{"next": null, "results": [
+				{"id": 10, "name": "tagged", "tags": [7, 8]},
+				{"id": 11, "name": "untagged", "tags": [7]}
+			]}`))
+		default:
+			t.Errorf("unexpected request to %s", req.URL.Path)
+		}
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+	client.sensitiveFeatureTag = "secret"
+
+	// When
+	tagged, err := client.IsSensitiveFeature("", 1, 10)
+	assert.NoError(t, err)
+	untagged, err := client.IsSensitiveFeature("", 1, 11)
+
+	// Then
+	assert.NoError(t, err)
+	assert.True(t, tagged)
+	assert.False(t, untagged)
+}
+
+func TestIsSensitiveFeatureOutsideSensitiveEnvironments(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		var err error
+		switch req.URL.Path {
+		case "/projects/1/tags/":
+			_, err = rw.Write([]byte(`[{"id": 8, "label": "secret"}]`))
+		case "/projects/1/features/":
+			_, err = rw.Write([]byte(`{"next": null, "results": [{"id": 10, "name": "tagged", "tags": [8]}]}`))
+		default:
+			t.Errorf("unexpected request to %s", req.URL.Path)
+		}
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+	client.sensitiveFeatureTag = "secret"
+	client.sensitiveEnvironmentKeys = map[string]bool{"production": true}
+
+	// When
+	production, err := client.IsSensitiveFeature("production", 1, 10)
+	assert.NoError(t, err)
+	development, err := client.IsSensitiveFeature("development", 1, 10)
+	assert.NoError(t, err)
+	shared, err := client.IsSensitiveFeature("", 1, 10)
+
+	// Then
+	assert.NoError(t, err)
+	assert.True(t, production)
+	assert.False(t, development)
+	assert.True(t, shared)
+}
+
+func TestGetEdgeIdentityOverride(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/environments/env_key/edge-identities/identity-uuid/edge-featurestates/", req.URL.Path)
+		assert.Equal(t, "2", req.URL.Query().Get("feature"))
+		rw.Header().Set("Content-Type", "application/json")
+		_, err := rw.Write([]byte(`[
+			{"featurestate_uuid": "fs-1", "feature": 1, "enabled": true, "feature_state_value": null},
+			{"featurestate_uuid": "fs-2", "feature": 2, "enabled": false, "feature_state_value": 42}
+		]`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+
+	// When
+	override, err := client.GetIdentityOverride("env_key", "identity-uuid", 2, true)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, "fs-2", override.ID)
+	assert.False(t, override.Enabled)
+	assert.Equal(t, "int", override.FeatureStateValue.Type)
+	assert.Equal(t, int64(42), *override.FeatureStateValue.IntegerValue)
+}
+
+func TestCreateIdentityOverride(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/environments/env_key/identities/7/featurestates/", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"feature": 2, "enabled": true, "feature_state_value": "value", "multivariate_feature_state_values": []}`, string(body))
+		rw.Header().Set("Content-Type", "application/json")
+		_, err = rw.Write([]byte(`{"id": 12, "feature": 2, "enabled": true, "feature_state_value": "value"}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+	value := "value"
+	override := IdentityOverride{
+		Feature:           2,
+		Enabled:           true,
+		FeatureStateValue: &flagsmithapi.FeatureStateValue{Type: "unicode", StringValue: &value},
+	}
+
+	// When
+	err := client.CreateIdentityOverride("env_key", "7", false, &override)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, "12", override.ID)
+}
+
+func TestGetEdgeIdentityTraits(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/environments/env_key/edge-identities/identity-uuid/list-traits/", req.URL.Path)
+		rw.Header().Set("Content-Type", "application/json")
+		_, err := rw.Write([]byte(`[
+			{"trait_key": "name", "trait_value": "john"},
+			{"trait_key": "age", "trait_value": 42},
+			{"trait_key": "score", "trait_value": 4.5},
+			{"trait_key": "premium", "trait_value": true}
+		]`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+
+	// When
+	traits, err := client.GetIdentityTraits("env_key", "identity-uuid", true)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, "john", *traits["name"].StringValue)
+	assert.Equal(t, 42, *traits["age"].IntegerValue)
+	assert.Equal(t, 4.5, *traits["score"].FloatValue)
+	assert.True(t, *traits["premium"].BooleanValue)
+}
+
+func TestUpdateFeatureStateMultivariateValues(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPatch, req.Method)
+		assert.Equal(t, "/features/featurestates/7/", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"multivariate_feature_state_values": [{"id": 3, "multivariate_feature_option": 1, "percentage_allocation": 30}, {"multivariate_feature_option": 2, "percentage_allocation": 70}]}`, string(body))
+		rw.Header().Set("Content-Type", "application/json")
+		_, err = rw.Write([]byte(`{}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+	valueID := int64(3)
+
+	// When
+	err := client.UpdateFeatureStateMultivariateValues(&flagsmithapi.FeatureState{ID: 7}, []MultivariateFeatureStateValue{
+		{ID: &valueID, MultivariateFeatureOption: 1, PercentageAllocation: 30},
+		{MultivariateFeatureOption: 2, PercentageAllocation: 70},
+	})
+
+	// Then
+	assert.NoError(t, err)
+}
+
+func TestGetEnvironmentByID(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		var err error
+		switch req.URL.Path {
+		case "/projects/":
+			_, err = rw.Write([]byte(`[{"id": 1, "name": "first"}, {"id": 2, "name": "second"}]`))
+		case "/environments/":
+			_, err = rw.Write([]byte(fmt.Sprintf(`{"next": null, "results": [{"id": %s0, "api_key": "env_%s", "project": %s}]}`,
+				req.URL.Query().Get("project"), req.URL.Query().Get("project"), req.URL.Query().Get("project"))))
+		default:
+			t.Errorf("unexpected request to %s", req.URL.Path)
+		}
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+
+	// When
+	environment, err := client.GetEnvironmentByID(20)
+	_, missingErr := client.GetEnvironmentByID(30)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, "env_2", environment.APIKey)
+	assert.Equal(t, int64(2), environment.ProjectID)
+	assert.Error(t, missingErr)
+}
+
+func TestFeatureRulesValidate(t *testing.T) {
+	// Given
+	lowerCase := FeatureRules{OnlyAllowLowerCase: true}
+	regex := FeatureRules{Regex: "[a-z]+_[a-z]+"}
+	unparsable := FeatureRules{Regex: "(?<=a)b"}
+
+	// Then
+	assert.NoError(t, lowerCase.ValidateName("new_checkout"))
+	assert.Error(t, lowerCase.ValidateName("New_checkout"))
+	assert.NoError(t, regex.ValidateName("new_checkout"))
+	// The regex must match the whole name
+	assert.Error(t, regex.ValidateName("new_checkout_v2"))
+	assert.Error(t, regex.ValidateName("checkout"))
+	assert.NoError(t, unparsable.ValidateName("anything"))
+	assert.NoError(t, FeatureRules{}.ValidateName("Anything"))
+}
+
+func TestFeatureRulesValidateOwners(t *testing.T) {
+	// Given
+	enforced := FeatureRules{ProjectName: "payments", EnforceOwners: true}
+
+	// Then
+	assert.NoError(t, enforced.ValidateOwners(1))
+	assert.ErrorContains(t, enforced.ValidateOwners(0), `project "payments"`)
+	assert.NoError(t, FeatureRules{}.ValidateOwners(0))
+}
+
+func TestGetPlannedFeatureRules(t *testing.T) {
+	// Given
+	client := newFSClient("master_api_key", "http://localhost")
+	payments := FeatureRules{ProjectName: "payments", OrganisationID: 1, OnlyAllowLowerCase: true}
+
+	// When
+	_, noneOk := client.GetPlannedFeatureRules()
+	client.SetPlannedFeatureRules(payments)
+	client.SetPlannedFeatureRules(payments)
+	rules, oneOk := client.GetPlannedFeatureRules()
+	client.SetPlannedFeatureRules(FeatureRules{ProjectName: "checkout", OrganisationID: 1})
+	_, twoOk := client.GetPlannedFeatureRules()
+
+	// Then
+	assert.False(t, noneOk)
+	assert.True(t, oneOk)
+	assert.Equal(t, payments, rules)
+	// The project of a feature can not be told apart between two new ones
+	assert.False(t, twoOk)
+}
+
+func TestGetOrganisationMembersRefreshesOnMiss(t *testing.T) {
+	// Given
+	userListings := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		var err error
+		switch req.URL.Path {
+		case "/organisations/1/users/":
+			userListings++
+			users := `[{"id": 3, "email": "alice@example.com"}]`
+			if userListings > 1 {
+				users = `[{"id": 3, "email": "alice@example.com"}, {"id": 4, "email": "bob@example.com"}]`
+			}
+			_, err = rw.Write([]byte(users))
+		case "/organisations/1/groups/":
+			_, err = rw.Write([]byte(`{"next": null, "results": [{"id": 5, "name": "payments"}]}`))
+		default:
+			t.Errorf("unexpected request to %s", req.URL.Path)
+		}
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+
+	// When
+	alice, err := client.GetUserIDs(1, []string{"alice@example.com"})
+	assert.NoError(t, err)
+	emails, err := client.GetUserEmails(1, []int64{3})
+	assert.NoError(t, err)
+	bob, err := client.GetUserIDs(1, []string{"bob@example.com"})
+	assert.NoError(t, err)
+	groups, err := client.GetGroupNames(1, []int64{5})
+	assert.NoError(t, err)
+	_, err = client.GetGroupIDs(1, []string{"unknown"})
+
+	// Then
+	assert.Equal(t, []int64{3}, alice)
+	assert.Equal(t, []string{"alice@example.com"}, emails)
+	assert.Equal(t, []int64{4}, bob)
+	assert.Equal(t, []string{"payments"}, groups)
+	assert.ErrorContains(t, err, "groups [unknown] not found in organisation 1")
+	assert.Equal(t, 3, userListings)
+}
+
+func TestGetTagIDs(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		assert.Equal(t, "/projects/1/tags/", req.URL.Path)
+		_, err := rw.Write([]byte(`[{"id": 7, "label": "experiment"}, {"id": 8, "label": "deprecated"}]`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+
+	// When
+	tagIDs, err := client.GetTagIDs(1, []string{"deprecated", "experiment"})
+	assert.NoError(t, err)
+	names, err := client.GetTagNames(1, []int64{7})
+	assert.NoError(t, err)
+	_, err = client.GetTagIDs(1, []string{"beta"})
+
+	// Then
+	assert.Equal(t, []int64{8, 7}, tagIDs)
+	assert.Equal(t, []string{"experiment"}, names)
+	assert.Equal(t, TagNotFoundError{ProjectID: 1, Names: []string{"beta"}}, err)
+}
+
+func TestClaimFeatureState(t *testing.T) {
+	// Given
+	client := newFSClient("master_api_key", "http://localhost")
+
+	// When
+	first := client.ClaimFeatureState("env_key", 1, featureStateManagerFeature)
+	again := client.ClaimFeatureState("env_key", 1, featureStateManagerFeature)
+	otherEnvironment := client.ClaimFeatureState("other_env_key", 1, featureStateManagerFeatureState)
+	conflict := client.ClaimFeatureState("env_key", 1, featureStateManagerFeatureState)
+
+	// Then
+	assert.NoError(t, first)
+	assert.NoError(t, again)
+	assert.NoError(t, otherEnvironment)
+	assert.ErrorContains(t, conflict, "already managed by the environment_values of a flagsmith_feature resource")
+}
+
+func TestGetMetadataFieldsRefreshesOnMiss(t *testing.T) {
+	// Given
+	modelFieldListings := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		var err error
+		switch req.URL.Path {
+		case "/organisations/1/metadata-model-fields/supported-content-types/":
+			_, err = rw.Write([]byte(`[{"id": 20, "model": "feature"}, {"id": 21, "model": "segment"}, {"id": 22, "model": "environment"}]`))
+		case "/organisations/1/metadata-model-fields/supported-required-for-models/":
+			assert.Equal(t, "feature", req.URL.Query().Get("model_name"))
+			_, err = rw.Write([]byte(`[{"id": 23, "model": "project"}]`))
+		case "/metadata/fields/":
+			assert.Equal(t, "1", req.URL.Query().Get("organisation"))
+			_, err = rw.Write([]byte(`[{"id": 3, "name": "jira", "type": "url", "organisation": 1}, {"id": 4, "name": "cost", "type": "int", "organisation": 1}]`))
+		case "/organisations/1/metadata-model-fields/":
+			modelFieldListings++
+			modelFields := `[{"id": 30, "field": 3, "content_type": 20, "is_required_for": [{"content_type": 23, "object_id": 5}]}]`
+			if modelFieldListings > 1 {
+				modelFields = `[{"id": 30, "field": 3, "content_type": 20, "is_required_for": [{"content_type": 23, "object_id": 5}]}, {"id": 31, "field": 4, "content_type": 21, "is_required_for": []}]`
+			}
+			_, err = rw.Write([]byte(modelFields))
+		default:
+			t.Errorf("unexpected request to %s", req.URL.Path)
+		}
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+
+	// When
+	featureFields, err := client.GetMetadataFields(1, MetadataModelFeature, []string{"jira"})
+	assert.NoError(t, err)
+	required, err := client.GetRequiredMetadataFields(1, MetadataModelFeature, 5)
+	assert.NoError(t, err)
+	segmentFields, err := client.GetMetadataFieldsByModelFieldID(1, MetadataModelSegment, []int64{31})
+	assert.NoError(t, err)
+	model, err := client.GetMetadataModel(1, 22)
+	assert.NoError(t, err)
+	_, err = client.GetMetadataFields(1, MetadataModelEnvironment, []string{"jira"})
+
+	// Then
+	assert.Equal(t, []OrganisationMetadataField{{ModelFieldID: 30, Name: "jira", Type: "url", RequiredFor: []int64{5}}}, featureFields)
+	assert.Equal(t, []string{"jira"}, required)
+	assert.Equal(t, []OrganisationMetadataField{{ModelFieldID: 31, Name: "cost", Type: "int", RequiredFor: []int64{}}}, segmentFields)
+	assert.Equal(t, MetadataModelEnvironment, model)
+	assert.Equal(t, MetadataFieldNotAttachedError{Model: MetadataModelEnvironment, Names: []string{"jira"}}, err)
+	assert.Equal(t, 3, modelFieldListings)
+}
+
+func TestUpdateFeatureWithMetadataKeepsMetadata(t *testing.T) {
+	// Given
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		assert.Equal(t, "/projects/1/features/2/", req.URL.Path)
+		if req.Method == http.MethodPut {
+			encoded, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+			body = string(encoded)
+		}
+		_, err := rw.Write([]byte(`{"id": 2, "name": "checkout", "project": 1, "metadata": [{"model_field": 30, "field_data": "https://example.com"}]}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+	projectID, featureID := int64(1), int64(2)
+	feature := flagsmithapi.Feature{Name: "checkout", ProjectID: &projectID, ID: &featureID}
+
+	// When
+	err := client.UpdateFeatureWithMetadata(&feature, nil)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Contains(t, body, `"metadata":[{"model_field":30,"field_data":"https://example.com"}]`)
+}
+
+func TestValidateMetadataValue(t *testing.T) {
+	// Given
+	values := []struct {
+		fieldType string
+		value     string
+		valid     bool
+	}{
+		{MetadataFieldTypeInt, "42", true},
+		{MetadataFieldTypeInt, "4.2", false},
+		{MetadataFieldTypeBool, "false", true},
+		{MetadataFieldTypeBool, "yes", false},
+		{MetadataFieldTypeURL, "https://example.com/browse/FS-1", true},
+		{MetadataFieldTypeURL, "example.com", false},
+		{MetadataFieldTypeStr, "anything", true},
+		{MetadataFieldTypeMultiline, "any\nthing", true},
+	}
+
+	for _, v := range values {
+		// When
+		err := ValidateMetadataValue(v.fieldType, v.value)
+
+		// Then
+		if v.valid {
+			assert.NoError(t, err, v.value)
+		} else {
+			assert.Error(t, err, v.value)
+		}
+	}
+}
+
+func TestUpdateFeatureTagsKeepsOtherTags(t *testing.T) {
+	// Given
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		var err error
+		switch {
+		case req.URL.Path == "/features/get-by-uuid/feature-uuid/":
+			_, err = rw.Write([]byte(`{"id": 2, "uuid": "feature-uuid", "name": "checkout", "project": 1, "tags": [7, 8]}`))
+		case req.URL.Path == "/projects/1/":
+			_, err = rw.Write([]byte(`{"id": 1, "uuid": "project-uuid", "name": "shop"}`))
+		case req.URL.Path == "/projects/1/features/2/" && req.Method == http.MethodGet:
+			_, err = rw.Write([]byte(`{"id": 2, "name": "checkout", "project": 1, "metadata": []}`))
+		case req.URL.Path == "/projects/1/features/2/" && req.Method == http.MethodPut:
+			encoded, readErr := io.ReadAll(req.Body)
+			assert.NoError(t, readErr)
+			body = string(encoded)
+			_, err = rw.Write(encoded)
+		default:
+			t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		}
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+
+	// When
+	err := client.UpdateFeatureTags("feature-uuid", []int64{9}, []int64{7})
+
+	// Then
+	assert.NoError(t, err)
+	assert.Contains(t, body, `"tags":[8,9]`)
+}
+
+func TestArchiveFeatureKeepsTags(t *testing.T) {
+	// Given
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		var err error
+		switch {
+		case req.URL.Path == "/features/get-by-uuid/feature-uuid/":
+			_, err = rw.Write([]byte(`{"id": 2, "uuid": "feature-uuid", "name": "checkout", "project": 1, "tags": [7, 8]}`))
+		case req.URL.Path == "/projects/1/":
+			_, err = rw.Write([]byte(`{"id": 1, "uuid": "project-uuid", "name": "shop"}`))
+		case req.URL.Path == "/projects/1/features/2/" && req.Method == http.MethodGet:
+			_, err = rw.Write([]byte(`{"id": 2, "name": "checkout", "project": 1, "metadata": []}`))
+		case req.URL.Path == "/projects/1/features/2/" && req.Method == http.MethodPut:
+			encoded, readErr := io.ReadAll(req.Body)
+			assert.NoError(t, readErr)
+			body = string(encoded)
+			_, err = rw.Write(encoded)
+		default:
+			t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		}
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClient("master_api_key", server.URL)
+
+	// When
+	err := client.ArchiveFeature("feature-uuid")
+
+	// Then
+	assert.NoError(t, err)
+	assert.Contains(t, body, `"tags":[7,8]`)
+	assert.Contains(t, body, `"is_archived":true`)
+}
+
+func TestCommitChangeRequestInvalidatesCachedFeatureStates(t *testing.T) {
+	// Given
+	listings := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		var err error
+		switch req.URL.Path {
+		case "/environments/env_key/featurestates/":
+			listings++
+			_, err = rw.Write([]byte(`{"next": null, "results": [{"id": 1, "uuid": "uuid-1", "feature_state_value": "one", "enabled": true, "feature": 1, "environment": 1}]}`))
+		case "/features/workflows/change-requests/5/commit/":
+			_, err = rw.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		}
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client := newFSClientWithCache("master_api_key", server.URL, true)
+	_, err := client.GetCachedEnvironmentFeatureState(context.Background(), "env_key", "uuid-1", 1)
+	assert.NoError(t, err)
+
+	// When
+	err = client.CommitChangeRequest(5)
+	assert.NoError(t, err)
+	_, err = client.GetCachedEnvironmentFeatureState(context.Background(), "env_key", "uuid-1", 1)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, 2, listings)
+}
@@ -0,0 +1,219 @@
+package flagsmith
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &environmentDiffDataResource{}
+
+func newEnvironmentDiffDataResource() datasource.DataSource {
+	return &environmentDiffDataResource{}
+}
+
+type environmentDiffDataResource struct {
+	client *fsClient
+}
+
+func (o *environmentDiffDataResource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_environment_diff"
+}
+
+func (o *environmentDiffDataResource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*fsClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	o.client = client
+}
+
+// environmentDiffFeatureStateSchema returns the schema of how a feature is
+// served by one of the compared environments
+func environmentDiffFeatureStateSchema(environment string) schema.SingleNestedAttribute {
+	weightsSchema := schema.MapAttribute{
+		Computed:            true,
+		ElementType:         types.Float64Type,
+		MarkdownDescription: "Percentage allocations keyed by multivariate option ID, null unless the feature is multivariate",
+	}
+	return schema.SingleNestedAttribute{
+		Computed:            true,
+		MarkdownDescription: fmt.Sprintf("Feature state of the feature in the %s environment, null if it has none", environment),
+		Attributes: map[string]schema.Attribute{
+			"enabled": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the feature is enabled",
+			},
+			"feature_state_value":  computedFeatureStateValueSchema("Value of the feature"),
+			"multivariate_weights": weightsSchema,
+			"segment_overrides": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Segment overrides of the feature, by priority",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"segment_id": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "ID of the segment",
+						},
+						"priority": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "Priority of the segment override",
+						},
+						"enabled": schema.BoolAttribute{
+							Computed:            true,
+							MarkdownDescription: "Whether the feature is enabled for the segment",
+						},
+						"feature_state_value":  computedFeatureStateValueSchema("Value of the feature for the segment"),
+						"multivariate_weights": weightsSchema,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (o *environmentDiffDataResource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Use this data source to compare the feature states of two environments of the same project, e.g: in `check` blocks or preconditions.",
+
+		Attributes: map[string]schema.Attribute{
+			"source_environment_key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Client side environment key of the first environment",
+			},
+			"target_environment_key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Client side environment key of the second environment",
+			},
+			"identical": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether every feature is served the same way by both environments, segment overrides and multivariate weights included",
+			},
+			"features": schema.MapNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Feature states of the features of the project in both environments, keyed by feature name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"feature_id": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "ID of the feature",
+						},
+						"identical": schema.BoolAttribute{
+							Computed:            true,
+							MarkdownDescription: "Whether the feature is served the same way by both environments",
+						},
+						"source": environmentDiffFeatureStateSchema("source"),
+						"target": environmentDiffFeatureStateSchema("target"),
+					},
+				},
+			},
+		},
+	}
+}
+
+// featureStateValueData returns the value of a compared feature state, marked
+// sensitive if its feature is
+func featureStateValueData(featureState *flagsmithapi.FeatureState, sensitive bool) *FeatureStateValue {
+	value := MakeEnvironmentFeatureStateDataFromClientFS(featureState).FeatureStateValue
+	if sensitive {
+		value.MarkSensitive()
+	}
+	return value
+}
+
+// featureStateData returns how a feature is served by one of the compared
+// environments
+func featureStateData(state *comparedFeatureState, overrides []*comparedFeatureState, sensitive bool) *EnvironmentDiffFeatureStateData {
+	if state == nil {
+		return nil
+	}
+	data := EnvironmentDiffFeatureStateData{
+		Enabled:             types.BoolValue(state.featureState.Enabled),
+		FeatureStateValue:   featureStateValueData(state.featureState, sensitive),
+		MultivariateWeights: weightsData(state.weights),
+		SegmentOverrides:    []EnvironmentDiffSegmentOverrideData{},
+	}
+	for _, override := range overrides {
+		data.SegmentOverrides = append(data.SegmentOverrides, EnvironmentDiffSegmentOverrideData{
+			Segment:             types.Int64PointerValue(override.featureState.Segment),
+			Priority:            types.Int64PointerValue(override.featureState.SegmentPriority),
+			Enabled:             types.BoolValue(override.featureState.Enabled),
+			FeatureStateValue:   featureStateValueData(override.featureState, sensitive),
+			MultivariateWeights: weightsData(override.weights),
+		})
+	}
+	return &data
+}
+
+func (o *environmentDiffDataResource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data EnvironmentDiffData
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	// Early return if the state is wrong
+	if diags.HasError() {
+		return
+	}
+
+	source, target, err := getEnvironmentPair(o.client, data.SourceEnvironmentKey.ValueString(), data.TargetEnvironmentKey.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get environments, got error: %s", err))
+		return
+	}
+	features, err := o.client.GetProjectFeatures(source.ProjectID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get features, got error: %s", err))
+		return
+	}
+	compared, err := compareEnvironments(o.client, source, target, features, true, true)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to compare environments, got error: %s", err))
+		return
+	}
+
+	data.Identical = types.BoolValue(true)
+	data.Features = map[string]EnvironmentDiffFeatureData{}
+	for i := range compared {
+		c := &compared[i]
+		sourceSensitive, err := o.client.IsSensitiveFeature(source.APIKey, source.ProjectID, *c.feature.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature tags, got error: %s", err))
+			return
+		}
+		targetSensitive, err := o.client.IsSensitiveFeature(target.APIKey, target.ProjectID, *c.feature.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature tags, got error: %s", err))
+			return
+		}
+		identical := c.identical()
+		if !identical {
+			data.Identical = types.BoolValue(false)
+		}
+		data.Features[c.feature.Name] = EnvironmentDiffFeatureData{
+			FeatureID: types.Int64Value(*c.feature.ID),
+			Identical: types.BoolValue(identical),
+			Source:    featureStateData(c.source, c.sourceOverrides, sourceSensitive),
+			Target:    featureStateData(c.target, c.targetOverrides, targetSensitive),
+		}
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+}
@@ -0,0 +1,68 @@
+package flagsmith_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccEnvironmentDiffDataResource(t *testing.T) {
+	featureName := acctest.RandString(10)
+	environmentName := acctest.RandString(10)
+	dataSourceName := "data.flagsmith_environment_diff.test_diff"
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEnvironmentDiffDataResourceConfig(featureName, environmentName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "identical", "false"),
+					resource.TestCheckResourceAttr(dataSourceName, fmt.Sprintf("features.%s.identical", featureName), "false"),
+					resource.TestCheckResourceAttrPair(dataSourceName, fmt.Sprintf("features.%s.feature_id", featureName), "flagsmith_feature.test_feature", "id"),
+					resource.TestCheckResourceAttr(dataSourceName, fmt.Sprintf("features.%s.source.enabled", featureName), "true"),
+					resource.TestCheckResourceAttr(dataSourceName, fmt.Sprintf("features.%s.source.feature_state_value.string_value", featureName), "changed"),
+					resource.TestCheckResourceAttr(dataSourceName, fmt.Sprintf("features.%s.target.enabled", featureName), "false"),
+					resource.TestCheckResourceAttr(dataSourceName, fmt.Sprintf("features.%s.target.feature_state_value.string_value", featureName), "initial"),
+					resource.TestCheckResourceAttr(dataSourceName, fmt.Sprintf("features.%s.target.segment_overrides.#", featureName), "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccEnvironmentDiffDataResourceConfig(featureName, environmentName string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name  = "%s"
+  project_uuid  = "%s"
+  type          = "STANDARD"
+  initial_value = "initial"
+}
+
+resource "flagsmith_environment" "test_target" {
+  name       = "%s"
+  project_id = %d
+  depends_on = [flagsmith_feature.test_feature]
+}
+
+resource "flagsmith_feature_state" "test_source" {
+  enabled         = true
+  environment_key = "%s"
+  feature_id      = flagsmith_feature.test_feature.id
+  feature_state_value = {
+    type         = "unicode"
+    string_value = "changed"
+  }
+}
+
+data "flagsmith_environment_diff" "test_diff" {
+  source_environment_key = flagsmith_feature_state.test_source.environment_key
+  target_environment_key = flagsmith_environment.test_target.api_key
+}
+`, providerConfig(), featureName, projectUUID(), environmentName, projectID(), environmentKey())
+}
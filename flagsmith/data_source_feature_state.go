@@ -0,0 +1,222 @@
+package flagsmith
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &featureStateDataResource{}
+var _ datasource.DataSourceWithConfigValidators = &featureStateDataResource{}
+
+func newFeatureStateDataResource() datasource.DataSource {
+	return &featureStateDataResource{}
+}
+
+type featureStateDataResource struct {
+	client *fsClient
+}
+
+func (o *featureStateDataResource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_feature_state"
+}
+
+func (o *featureStateDataResource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*fsClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	o.client = client
+}
+func (o *featureStateDataResource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Use this data source to read the feature state of a feature in an environment, or of one of its segment overrides.",
+
+		Attributes: map[string]schema.Attribute{
+			"environment_key": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Client side environment key associated with the environment. NOTE: One of environment_key, environment_id or environment_uuid must be set",
+			},
+			"environment_id": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "ID of the environment",
+			},
+			"environment_uuid": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "UUID of the environment",
+			},
+			"feature_id": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "ID of the feature. NOTE: One of feature_id, feature_uuid or feature_name must be set",
+			},
+			"feature_uuid": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "UUID of the feature",
+			},
+			"feature_name": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Name of the feature",
+			},
+			"segment_id": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "ID of the segment, to read the segment override of the segment instead of the environment default",
+			},
+			"id": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the feature state",
+			},
+			"uuid": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "UUID of the feature state",
+			},
+			"enabled": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the feature is enabled",
+			},
+			"value": schema.DynamicAttribute{
+				Computed:            true,
+				MarkdownDescription: "Value of the feature as a plain string, number or bool depending on its type. Null for the features tagged with the `sensitive_feature_tag` of the provider, read `feature_state_value.sensitive_string_value` instead",
+			},
+			"feature_state_value": computedFeatureStateValueSchema("Value of the feature along with its type"),
+			"segment_priority": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Priority of the segment override, null for the environment default",
+			},
+			"feature_segment_id": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the feature segment of the segment override, null for the environment default",
+			},
+			"updated_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Time the feature state was last updated at",
+			},
+		},
+	}
+}
+
+func (o *featureStateDataResource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("environment_key"),
+			path.MatchRoot("environment_id"),
+			path.MatchRoot("environment_uuid"),
+		),
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("feature_id"),
+			path.MatchRoot("feature_uuid"),
+			path.MatchRoot("feature_name"),
+		),
+	}
+}
+
+// getEnvironment returns the environment looked up by key, ID or UUID
+func (o *featureStateDataResource) getEnvironment(data *FeatureStateDataSourceData) (*flagsmithapi.Environment, error) {
+	switch {
+	case !data.EnvironmentKey.IsNull():
+		return o.client.GetEnvironment(data.EnvironmentKey.ValueString())
+	case !data.EnvironmentUUID.IsNull():
+		return o.client.GetEnvironmentByUUID(data.EnvironmentUUID.ValueString())
+	}
+	return o.client.GetEnvironmentByID(data.Environment.ValueInt64())
+}
+
+// getFeature returns the feature of the project looked up by ID, UUID or name
+func (o *featureStateDataResource) getFeature(data *FeatureStateDataSourceData, projectID int64) (*flagsmithapi.Feature, error) {
+	features, err := o.client.GetProjectFeatures(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range features {
+		feature := &features[i]
+		switch {
+		case !data.Feature.IsNull() && feature.ID != nil && *feature.ID == data.Feature.ValueInt64(),
+			!data.FeatureUUID.IsNull() && feature.UUID == data.FeatureUUID.ValueString(),
+			!data.FeatureName.IsNull() && feature.Name == data.FeatureName.ValueString():
+			return feature, nil
+		}
+	}
+	return nil, fmt.Errorf("feature not found in project %d", projectID)
+}
+
+func (o *featureStateDataResource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FeatureStateDataSourceData
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	// Early return if the state is wrong
+	if diags.HasError() {
+		return
+	}
+
+	environment, err := o.getEnvironment(&data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get environment, got error: %s", err))
+		return
+	}
+	feature, err := o.getFeature(&data, environment.ProjectID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get feature, got error: %s", err))
+		return
+	}
+
+	var featureState *flagsmithapi.FeatureState
+	if data.Segment.IsNull() {
+		featureState, err = o.client.GetEnvironmentFeatureState(environment.APIKey, *feature.ID)
+	} else {
+		featureState, err = o.client.GetSegmentOverride(environment.ID, *feature.ID, data.Segment.ValueInt64())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get feature state, got error: %s", err))
+		return
+	}
+	featureState.EnvironmentKey = environment.APIKey
+	featureState.Environment = &environment.ID
+	details, err := o.client.GetFeatureStateDetails(featureState.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get feature state, got error: %s", err))
+		return
+	}
+	sensitive, err := o.client.IsSensitiveFeature(environment.APIKey, environment.ProjectID, *feature.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature tags, got error: %s", err))
+		return
+	}
+
+	resourceData := MakeFeatureStateDataSourceDataFromClientFS(featureState)
+	resourceData.EnvironmentUUID = types.StringValue(environment.UUID)
+	resourceData.FeatureUUID = types.StringValue(feature.UUID)
+	resourceData.FeatureName = types.StringValue(feature.Name)
+	resourceData.UpdatedAt = types.StringValue(details.UpdatedAt)
+	if sensitive {
+		resourceData.FeatureStateValue.MarkSensitive()
+		resourceData.Value = types.DynamicNull()
+	}
+
+	diags = resp.State.Set(ctx, &resourceData)
+	resp.Diagnostics.Append(diags...)
+
+}
@@ -0,0 +1,63 @@
+package flagsmith_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccFeatureStateDataResource(t *testing.T) {
+	featureName := acctest.RandString(10)
+	dataSourceName := "data.flagsmith_feature_state.test_feature_state"
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFeatureStateDataResourceConfig(featureName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "environment_key", environmentKey()),
+					resource.TestCheckResourceAttr(dataSourceName, "environment_id", strconv.Itoa(environmentID())),
+					resource.TestCheckResourceAttrPair(dataSourceName, "feature_id", "flagsmith_feature.test_feature", "id"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "id", "flagsmith_feature_state.test_feature_state", "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "enabled", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "value", "25"),
+					resource.TestCheckResourceAttr(dataSourceName, "feature_state_value.type", "int"),
+					resource.TestCheckResourceAttr(dataSourceName, "feature_state_value.integer_value", "25"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "updated_at"),
+					resource.TestCheckNoResourceAttr(dataSourceName, "segment_priority"),
+				),
+			},
+		},
+	})
+}
+
+func testAccFeatureStateDataResourceConfig(featureName string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name = "%s"
+  project_uuid = "%s"
+  type         = "STANDARD"
+}
+
+resource "flagsmith_feature_state" "test_feature_state" {
+  enabled         = true
+  environment_key = "%s"
+  feature_id      = flagsmith_feature.test_feature.id
+  feature_state_value = {
+    type          = "int"
+    integer_value = 25
+  }
+}
+
+data "flagsmith_feature_state" "test_feature_state" {
+  environment_key = flagsmith_feature_state.test_feature_state.environment_key
+  feature_name    = flagsmith_feature.test_feature.feature_name
+}
+`, providerConfig(), featureName, projectUUID(), environmentKey())
+}
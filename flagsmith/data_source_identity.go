@@ -0,0 +1,210 @@
+package flagsmith
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &identityDataResource{}
+
+func newIdentityDataResource() datasource.DataSource {
+	return &identityDataResource{}
+}
+
+type identityDataResource struct {
+	client *fsClient
+}
+
+func (o *identityDataResource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_identity"
+}
+
+func (o *identityDataResource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*fsClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	o.client = client
+}
+func (o *identityDataResource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Use this data source to look up a Flagsmith identity along with its traits and the flags evaluated for it.",
+
+		Attributes: map[string]schema.Attribute{
+			"environment_key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Client side environment key associated with the environment",
+			},
+			"identifier": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Identifier of the identity",
+			},
+			"edge_identities": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Use the Edge identities API. Defaults to whether the project of the environment stores its identities in Edge(e.g: on Flagsmith SaaS)",
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the identity, its UUID for Edge identities",
+			},
+			"traits": schema.MapNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Traits of the identity keyed by trait key",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"string_value": schema.StringAttribute{
+							MarkdownDescription: "String value of the trait",
+							Computed:            true,
+						},
+						"integer_value": schema.Int64Attribute{
+							MarkdownDescription: "Integer value of the trait",
+							Computed:            true,
+						},
+						"float_value": schema.Float64Attribute{
+							MarkdownDescription: "Float value of the trait",
+							Computed:            true,
+						},
+						"boolean_value": schema.BoolAttribute{
+							MarkdownDescription: "Boolean value of the trait",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"flags": schema.MapNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Flags evaluated for the identity keyed by feature name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"feature_id": schema.Int64Attribute{
+							MarkdownDescription: "ID of the feature",
+							Computed:            true,
+						},
+						"enabled": schema.BoolAttribute{
+							MarkdownDescription: "Whether the feature is enabled for the identity",
+							Computed:            true,
+						},
+						"feature_state_value": computedFeatureStateValueSchema("Value of the feature for the identity"),
+					},
+				},
+			},
+		},
+	}
+}
+
+// computedFeatureStateValueSchema returns the schema of a feature state value
+// read by a data source
+func computedFeatureStateValueSchema(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: description,
+		Computed:            true,
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Type of the feature state value, can be `unicode`, `int` or `bool`",
+				Computed:            true,
+			},
+			"string_value": schema.StringAttribute{
+				MarkdownDescription: "String value of the feature if the type is `unicode` and the feature is not sensitive",
+				Computed:            true,
+			},
+			"sensitive_string_value": schema.StringAttribute{
+				MarkdownDescription: "String value of the feature if the type is `unicode` and the feature is tagged as sensitive",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"integer_value": schema.Int64Attribute{
+				MarkdownDescription: "Integer value of the feature if the type is `int`",
+				Computed:            true,
+			},
+			"boolean_value": schema.BoolAttribute{
+				MarkdownDescription: "Boolean value of the feature if the type is `bool`",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (o *identityDataResource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data IdentityDataSourceData
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	// Early return if the state is wrong
+	if diags.HasError() {
+		return
+	}
+	environmentKey := data.EnvironmentKey.ValueString()
+
+	edge := data.EdgeIdentities.ValueBool()
+	if data.EdgeIdentities.IsNull() || data.EdgeIdentities.IsUnknown() {
+		var err error
+		edge, err = o.client.UsesEdgeIdentities(environmentKey)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read project, got error: %s", err))
+			return
+		}
+	}
+	data.EdgeIdentities = types.BoolValue(edge)
+
+	identityID, err := o.client.GetIdentityID(environmentKey, data.Identifier.ValueString(), edge)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get identity, got error: %s", err))
+		return
+	}
+	data.ID = types.StringValue(identityID)
+
+	traits, err := o.client.GetIdentityTraits(environmentKey, identityID, edge)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get traits, got error: %s", err))
+		return
+	}
+	data.Traits = map[string]TraitValue{}
+	for key, trait := range traits {
+		data.Traits[key] = MakeTraitValueFromClientTrait(&trait, nil)
+	}
+
+	flags, err := o.client.GetIdentityFlags(environmentKey, identityID, edge)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get flags, got error: %s", err))
+		return
+	}
+	data.Flags = map[string]IdentityFlagData{}
+	for _, flag := range flags {
+		featureID := *flag.Feature.ID
+		value := MakeFeatureStateValueFromClientFSV(flag.FeatureStateValue)
+		sensitive, err := o.client.IsSensitiveEnvironmentFeature(environmentKey, featureID)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature tags, got error: %s", err))
+			return
+		}
+		if sensitive {
+			value.MarkSensitive()
+		}
+		data.Flags[flag.Feature.Name] = IdentityFlagData{
+			FeatureID:         types.Int64Value(featureID),
+			Enabled:           types.BoolValue(flag.Enabled),
+			FeatureStateValue: &value,
+		}
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+}
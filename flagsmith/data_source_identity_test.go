@@ -0,0 +1,69 @@
+package flagsmith_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccIdentityDataResource(t *testing.T) {
+	featureName := acctest.RandString(10)
+	identifier := acctest.RandString(10) + "@example.com"
+	dataSourceName := "data.flagsmith_identity.test_identity"
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIdentityDataResourceConfig(featureName, identifier),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "identifier", identifier),
+					resource.TestCheckResourceAttrPair(dataSourceName, "id", "flagsmith_identity.test_identity", "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "traits.age.integer_value", "42"),
+					resource.TestCheckResourceAttrPair(dataSourceName, fmt.Sprintf("flags.%s.feature_id", featureName), "flagsmith_feature.test_feature", "id"),
+					resource.TestCheckResourceAttr(dataSourceName, fmt.Sprintf("flags.%s.enabled", featureName), "true"),
+					resource.TestCheckResourceAttr(dataSourceName, fmt.Sprintf("flags.%s.feature_state_value.string_value", featureName), "overridden"),
+				),
+			},
+		},
+	})
+}
+
+func testAccIdentityDataResourceConfig(featureName, identifier string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name = "%s"
+  project_uuid = "%s"
+  type         = "STANDARD"
+}
+
+resource "flagsmith_identity" "test_identity" {
+  environment_key = "%s"
+  identifier      = "%s"
+  traits = {
+    age = { integer_value = 42 }
+  }
+}
+
+resource "flagsmith_identity_override" "test_override" {
+  environment_key = flagsmith_identity.test_identity.environment_key
+  identifier      = flagsmith_identity.test_identity.identifier
+  feature_id      = flagsmith_feature.test_feature.id
+  enabled         = true
+  feature_state_value = {
+    type         = "unicode"
+    string_value = "overridden"
+  }
+}
+
+data "flagsmith_identity" "test_identity" {
+  environment_key = flagsmith_identity.test_identity.environment_key
+  identifier      = flagsmith_identity.test_identity.identifier
+  depends_on      = [flagsmith_identity_override.test_override]
+}
+`, providerConfig(), featureName, projectUUID(), environmentKey(), identifier)
+}
@@ -0,0 +1,198 @@
+package flagsmith
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// comparedFeatureState is a feature state of an environment along with the
+// weights of its multivariate options, which are nil if they are not compared
+type comparedFeatureState struct {
+	featureState *flagsmithapi.FeatureState
+	weights      []MultivariateFeatureStateValue
+}
+
+func (s *comparedFeatureState) equal(other *comparedFeatureState) bool {
+	current := MakeEnvironmentFeatureStateDataFromClientFS(s.featureState)
+	if !current.Equal(MakeEnvironmentFeatureStateDataFromClientFS(other.featureState)) {
+		return false
+	}
+	if !types.Int64PointerValue(s.featureState.SegmentPriority).Equal(types.Int64PointerValue(other.featureState.SegmentPriority)) {
+		return false
+	}
+	if len(s.weights) != len(other.weights) {
+		return false
+	}
+	weights := map[int64]float64{}
+	for _, weight := range other.weights {
+		weights[weight.MultivariateFeatureOption] = weight.PercentageAllocation
+	}
+	for _, weight := range s.weights {
+		if otherWeight, ok := weights[weight.MultivariateFeatureOption]; !ok || otherWeight != weight.PercentageAllocation {
+			return false
+		}
+	}
+	return true
+}
+
+// weightsData returns the given weights keyed by multivariate option ID, nil
+// if the weights are not compared
+func weightsData(weights []MultivariateFeatureStateValue) map[string]types.Float64 {
+	if weights == nil {
+		return nil
+	}
+	data := map[string]types.Float64{}
+	for _, weight := range weights {
+		data[strconv.FormatInt(weight.MultivariateFeatureOption, 10)] = types.Float64Value(weight.PercentageAllocation)
+	}
+	return data
+}
+
+// comparedFeature holds the feature states of a feature in the two compared
+// environments. The segment overrides are ordered by priority, and are nil if
+// they are not compared.
+type comparedFeature struct {
+	feature         *flagsmithapi.Feature
+	source          *comparedFeatureState
+	target          *comparedFeatureState
+	sourceOverrides []*comparedFeatureState
+	targetOverrides []*comparedFeatureState
+}
+
+// identical reports whether the feature is served the same way by both
+// environments
+func (f *comparedFeature) identical() bool {
+	if (f.source == nil) != (f.target == nil) || (f.source != nil && !f.source.equal(f.target)) {
+		return false
+	}
+	if len(f.sourceOverrides) != len(f.targetOverrides) {
+		return false
+	}
+	for i := range f.sourceOverrides {
+		if *f.sourceOverrides[i].featureState.Segment != *f.targetOverrides[i].featureState.Segment || !f.sourceOverrides[i].equal(f.targetOverrides[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// getEnvironmentPair returns the two environments to compare, which must be
+// distinct environments of the same project
+func getEnvironmentPair(client *fsClient, sourceKey, targetKey string) (*flagsmithapi.Environment, *flagsmithapi.Environment, error) {
+	if sourceKey == targetKey {
+		return nil, nil, fmt.Errorf("source and target environments must be different")
+	}
+	source, err := client.GetEnvironment(sourceKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := client.GetEnvironment(targetKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if source.ProjectID != target.ProjectID {
+		return nil, nil, fmt.Errorf("environments %q and %q must belong to the same project", sourceKey, targetKey)
+	}
+	return source, target, nil
+}
+
+// readWeights reads the weights of the multivariate options of the feature
+// state, if the feature is multivariate
+func readWeights(client *fsClient, state *comparedFeatureState, feature *flagsmithapi.Feature) error {
+	if feature.Type == nil || *feature.Type != "MULTIVARIATE" {
+		return nil
+	}
+	weights, err := client.GetFeatureStateMultivariateValues(state.featureState.ID)
+	if err != nil {
+		return err
+	}
+	state.weights = weights
+	return nil
+}
+
+// readSegmentOverrides returns the segment overrides of a feature in an
+// environment, by priority
+func readSegmentOverrides(client *fsClient, environmentID int64, feature *flagsmithapi.Feature, withWeights bool) ([]*comparedFeatureState, error) {
+	featureSegments, err := client.GetFeatureSegments(environmentID, *feature.ID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(featureSegments, func(i, j int) bool {
+		return types.Int64PointerValue(featureSegments[i].Priority).ValueInt64() < types.Int64PointerValue(featureSegments[j].Priority).ValueInt64()
+	})
+	overrides := make([]*comparedFeatureState, 0, len(featureSegments))
+	for _, featureSegment := range featureSegments {
+		featureState, err := client.GetFeatureSegmentFeatureState(environmentID, *feature.ID, *featureSegment.ID)
+		if err != nil {
+			return nil, err
+		}
+		featureState.Segment = featureSegment.Segment
+		featureState.SegmentPriority = featureSegment.Priority
+		override := comparedFeatureState{featureState: featureState}
+		if withWeights {
+			err = readWeights(client, &override, feature)
+			if err != nil {
+				return nil, err
+			}
+		}
+		overrides = append(overrides, &override)
+	}
+	return overrides, nil
+}
+
+// compareEnvironments reads the feature states of the given features in both
+// environments, along with their segment overrides and the weights of their
+// multivariate options if asked to
+func compareEnvironments(client *fsClient, source, target *flagsmithapi.Environment, features []flagsmithapi.Feature, withOverrides, withWeights bool) ([]comparedFeature, error) {
+	sourceFeatureStates, err := client.GetEnvironmentFeatureStates(source.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	targetFeatureStates, err := client.GetEnvironmentFeatureStates(target.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	sourceStates := map[int64]*flagsmithapi.FeatureState{}
+	for i := range sourceFeatureStates {
+		sourceStates[sourceFeatureStates[i].Feature] = &sourceFeatureStates[i]
+	}
+	targetStates := map[int64]*flagsmithapi.FeatureState{}
+	for i := range targetFeatureStates {
+		targetStates[targetFeatureStates[i].Feature] = &targetFeatureStates[i]
+	}
+
+	compared := make([]comparedFeature, 0, len(features))
+	for i := range features {
+		feature := &features[i]
+		c := comparedFeature{feature: feature}
+		if featureState, ok := sourceStates[*feature.ID]; ok {
+			c.source = &comparedFeatureState{featureState: featureState}
+		}
+		if featureState, ok := targetStates[*feature.ID]; ok {
+			c.target = &comparedFeatureState{featureState: featureState}
+		}
+		for _, state := range []*comparedFeatureState{c.source, c.target} {
+			if state != nil && withWeights {
+				if err = readWeights(client, state, feature); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if withOverrides {
+			c.sourceOverrides, err = readSegmentOverrides(client, source.ID, feature, withWeights)
+			if err != nil {
+				return nil, err
+			}
+			c.targetOverrides, err = readSegmentOverrides(client, target.ID, feature, withWeights)
+			if err != nil {
+				return nil, err
+			}
+		}
+		compared = append(compared, c)
+	}
+	return compared, nil
+}
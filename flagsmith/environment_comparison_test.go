@@ -0,0 +1,47 @@
+package flagsmith
+
+import (
+	"testing"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/stretchr/testify/assert"
+)
+
+func newComparedFeatureState(enabled bool, value string, segment, priority *int64, weights ...float64) *comparedFeatureState {
+	state := comparedFeatureState{featureState: &flagsmithapi.FeatureState{
+		Enabled:           enabled,
+		FeatureStateValue: &flagsmithapi.FeatureStateValue{Type: "unicode", StringValue: &value},
+		Segment:           segment,
+		SegmentPriority:   priority,
+	}}
+	for option, weight := range weights {
+		state.weights = append(state.weights, MultivariateFeatureStateValue{MultivariateFeatureOption: int64(option), PercentageAllocation: weight})
+	}
+	return &state
+}
+
+func TestComparedFeatureIdentical(t *testing.T) {
+	// Given
+	segment, otherSegment, first, second := int64(1), int64(2), int64(0), int64(1)
+	feature := comparedFeature{
+		source:          newComparedFeatureState(true, "value", nil, nil, 30, 70),
+		target:          newComparedFeatureState(true, "value", nil, nil, 30, 70),
+		sourceOverrides: []*comparedFeatureState{newComparedFeatureState(false, "segment", &segment, &first)},
+		targetOverrides: []*comparedFeatureState{newComparedFeatureState(false, "segment", &segment, &first)},
+	}
+	otherWeights := feature
+	otherWeights.target = newComparedFeatureState(true, "value", nil, nil, 50, 50)
+	otherValue := feature
+	otherValue.target = newComparedFeatureState(true, "other", nil, nil, 30, 70)
+	otherPriority := feature
+	otherPriority.targetOverrides = []*comparedFeatureState{newComparedFeatureState(false, "segment", &segment, &second)}
+	otherOverrides := feature
+	otherOverrides.targetOverrides = []*comparedFeatureState{newComparedFeatureState(false, "segment", &otherSegment, &first)}
+
+	// Then
+	assert.True(t, feature.identical())
+	assert.False(t, otherWeights.identical())
+	assert.False(t, otherValue.identical())
+	assert.False(t, otherPriority.identical())
+	assert.False(t, otherOverrides.identical())
+}
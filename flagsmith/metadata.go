@@ -0,0 +1,121 @@
+package flagsmith
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// metadataSchema returns the schema of the metadata of a feature, segment or
+// environment
+func metadataSchema(model string) schema.MapAttribute {
+	return schema.MapAttribute{
+		Optional:            true,
+		ElementType:         types.StringType,
+		MarkdownDescription: fmt.Sprintf("Metadata of the %s keyed by metadata field name. The fields must be attached to %ss with a flagsmith_metadata_model_field and the values suit their type. If unspecified, the metadata is left untouched", model, model),
+	}
+}
+
+// makeClientMetadata returns the metadata keyed by field name as the values of
+// the metadata model fields of the model, nil if metadata is
+func makeClientMetadata(client *fsClient, orgID int64, model string, metadata map[string]types.String) ([]Metadata, error) {
+	if metadata == nil {
+		return nil, nil
+	}
+	names := make([]string, 0, len(metadata))
+	for name := range metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields, err := client.GetMetadataFields(orgID, model, names)
+	if err != nil {
+		return nil, err
+	}
+	clientMetadata := make([]Metadata, 0, len(fields))
+	for i, field := range fields {
+		clientMetadata = append(clientMetadata, Metadata{ModelField: field.ModelFieldID, FieldData: metadata[names[i]].ValueString()})
+	}
+	return clientMetadata, nil
+}
+
+// makeMetadataData returns the values of the metadata model fields of the
+// model keyed by field name
+func makeMetadataData(client *fsClient, orgID int64, model string, clientMetadata []Metadata) (map[string]types.String, error) {
+	modelFieldIDs := make([]int64, 0, len(clientMetadata))
+	for _, item := range clientMetadata {
+		modelFieldIDs = append(modelFieldIDs, item.ModelField)
+	}
+	fields, err := client.GetMetadataFieldsByModelFieldID(orgID, model, modelFieldIDs)
+	if err != nil {
+		return nil, err
+	}
+	metadata := map[string]types.String{}
+	for i, field := range fields {
+		metadata[field.Name] = types.StringValue(clientMetadata[i].FieldData)
+	}
+	return metadata, nil
+}
+
+// validateMetadata errors for the metadata of an object of the model in the
+// project whose fields are not attached to the model, whose values do not suit
+// the type of their field, or that misses the fields required in the project.
+// Null metadata is left to Flagsmith, since it is not managed.
+func validateMetadata(ctx context.Context, client *fsClient, orgID, projectID int64, model string, metadata types.Map) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if metadata.IsNull() || metadata.IsUnknown() {
+		return diags
+	}
+	values := map[string]types.String{}
+	diags.Append(metadata.ElementsAs(ctx, &values, false)...)
+	if diags.HasError() {
+		return diags
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields, err := client.GetMetadataFields(orgID, model, names)
+	if err != nil {
+		if _, ok := err.(MetadataFieldNotAttachedError); ok {
+			diags.AddAttributeError(path.Root("metadata"), "Unknown Metadata Field", err.Error())
+			return diags
+		}
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read metadata fields, got error: %s", err))
+		return diags
+	}
+	for i, field := range fields {
+		value := values[names[i]]
+		if value.IsUnknown() {
+			continue
+		}
+		if err := ValidateMetadataValue(field.Type, value.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("metadata").AtMapKey(names[i]), "Invalid Metadata Value",
+				fmt.Sprintf("Metadata field %q is of type %q: %s", field.Name, field.Type, err))
+		}
+	}
+
+	required, err := client.GetRequiredMetadataFields(orgID, model, projectID)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read metadata fields, got error: %s", err))
+		return diags
+	}
+	missing := []string{}
+	for _, name := range required {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		diags.AddAttributeError(path.Root("metadata"), "Missing Required Metadata",
+			fmt.Sprintf("Metadata fields %s are required for the %ss of the project", strings.Join(missing, ", "), model))
+	}
+	return diags
+}
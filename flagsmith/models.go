@@ -2,21 +2,44 @@ package flagsmith
 
 import (
 	flagsmithapi "github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type FeatureStateValue struct {
-	Type         types.String `tfsdk:"type"`
-	StringValue  types.String `tfsdk:"string_value"`
-	IntegerValue types.Int64  `tfsdk:"integer_value"`
-	BooleanValue types.Bool   `tfsdk:"boolean_value"`
+	Type                 types.String `tfsdk:"type"`
+	StringValue          types.String `tfsdk:"string_value"`
+	SensitiveStringValue types.String `tfsdk:"sensitive_string_value"`
+	IntegerValue         types.Int64  `tfsdk:"integer_value"`
+	BooleanValue         types.Bool   `tfsdk:"boolean_value"`
+}
+
+// IsSensitive reports whether the value is held by `sensitive_string_value`
+func (f *FeatureStateValue) IsSensitive() bool {
+	return f != nil && !f.SensitiveStringValue.IsNull()
+}
+
+// MarkSensitive moves a string value to `sensitive_string_value`
+func (f *FeatureStateValue) MarkSensitive() {
+	if f == nil || f.StringValue.IsNull() {
+		return
+	}
+	f.SensitiveStringValue = f.StringValue
+	f.StringValue = types.StringNull()
 }
 
 func (f *FeatureStateValue) ToClientFSV() *flagsmithapi.FeatureStateValue {
 	switch f.Type.ValueString() {
 	case "unicode":
 		value := f.StringValue.ValueString()
+		if f.IsSensitive() {
+			value = f.SensitiveStringValue.ValueString()
+		}
 		return &flagsmithapi.FeatureStateValue{
 			Type:        "unicode",
 			StringValue: &value,
@@ -40,10 +63,11 @@ func (f *FeatureStateValue) ToClientFSV() *flagsmithapi.FeatureStateValue {
 func MakeFeatureStateValueFromClientFSV(clientFSV *flagsmithapi.FeatureStateValue) FeatureStateValue {
 	fsvType := clientFSV.Type
 	fsValue := FeatureStateValue{
-		Type:         types.StringValue(fsvType),
-		StringValue:  types.StringNull(),
-		IntegerValue: types.Int64Null(),
-		BooleanValue: types.BoolNull(),
+		Type:                 types.StringValue(fsvType),
+		StringValue:          types.StringNull(),
+		SensitiveStringValue: types.StringNull(),
+		IntegerValue:         types.Int64Null(),
+		BooleanValue:         types.BoolNull(),
 	}
 	switch fsvType {
 	case "unicode":
@@ -77,6 +101,59 @@ type FeatureStateResourceData struct {
 	Segment           types.Int64        `tfsdk:"segment_id"`
 	SegmentPriority   types.Int64        `tfsdk:"segment_priority"`
 	FeatureSegment    types.Int64        `tfsdk:"feature_segment_id"`
+
+	ChangeRequestMode   types.String `tfsdk:"change_request_mode"`
+	ChangeRequest       types.Int64  `tfsdk:"change_request_id"`
+	ChangeRequestStatus types.String `tfsdk:"change_request_status"`
+
+	LiveFrom        types.String `tfsdk:"live_from"`
+	ScheduledChange types.Int64  `tfsdk:"scheduled_change_id"`
+
+	FeatureVersion types.String `tfsdk:"feature_version_uuid"`
+
+	ManagedFields types.Set `tfsdk:"managed_fields"`
+}
+
+// Fields of a feature state that can be left out of `managed_fields`
+const (
+	ManagedFieldEnabled = "enabled"
+	ManagedFieldValue   = "value"
+)
+
+var managedFields = []string{ManagedFieldEnabled, ManagedFieldValue}
+
+// Manages reports whether Terraform owns the given field of the feature
+// state. Every field is managed unless `managed_fields` says otherwise.
+func (f *FeatureStateResourceData) Manages(field string) bool {
+	if f.ManagedFields.IsNull() || f.ManagedFields.IsUnknown() {
+		return true
+	}
+	for _, element := range f.ManagedFields.Elements() {
+		if managedField, ok := element.(types.String); ok && managedField.ValueString() == field {
+			return true
+		}
+	}
+	return false
+}
+
+// CopyUnmanagedFields sets the fields left out of `managed_fields` to their
+// values in other
+func (f *FeatureStateResourceData) CopyUnmanagedFields(other *FeatureStateResourceData) {
+	if !f.Manages(ManagedFieldEnabled) {
+		f.Enabled = other.Enabled
+	}
+	if !f.Manages(ManagedFieldValue) {
+		f.FeatureStateValue = other.FeatureStateValue
+	}
+}
+
+// IsScheduled reports whether the feature state goes live after now
+func (f *FeatureStateResourceData) IsScheduled(now time.Time) bool {
+	if f.LiveFrom.IsNull() || f.LiveFrom.IsUnknown() {
+		return false
+	}
+	liveFrom, err := time.Parse(time.RFC3339, f.LiveFrom.ValueString())
+	return err == nil && liveFrom.After(now)
 }
 
 func (f *FeatureStateResourceData) ToClientFS() *flagsmithapi.FeatureState {
@@ -121,6 +198,17 @@ func MakeFeatureStateResourceDataFromClientFS(clientFS *flagsmithapi.FeatureStat
 		Segment:           types.Int64Null(),
 		SegmentPriority:   types.Int64Null(),
 		FeatureSegment:    types.Int64Null(),
+
+		ChangeRequestMode:   types.StringNull(),
+		ChangeRequest:       types.Int64Null(),
+		ChangeRequestStatus: types.StringNull(),
+
+		LiveFrom:        types.StringNull(),
+		ScheduledChange: types.Int64Null(),
+
+		FeatureVersion: types.StringNull(),
+
+		ManagedFields: types.SetNull(types.StringType),
 	}
 	if clientFS.FeatureSegment != nil {
 		featureSegment := types.Int64Value(*clientFS.FeatureSegment)
@@ -141,6 +229,200 @@ func MakeFeatureStateResourceDataFromClientFS(clientFS *flagsmithapi.FeatureStat
 
 }
 
+type SegmentOverrideResourceData struct {
+	ID                types.Int64        `tfsdk:"id"`
+	UUID              types.String       `tfsdk:"uuid"`
+	Enabled           types.Bool         `tfsdk:"enabled"`
+	FeatureStateValue *FeatureStateValue `tfsdk:"feature_state_value"`
+	Feature           types.Int64        `tfsdk:"feature_id"`
+	Environment       types.Int64        `tfsdk:"environment_id"`
+	EnvironmentKey    types.String       `tfsdk:"environment_key"`
+	Segment           types.Int64        `tfsdk:"segment_id"`
+	SegmentPriority   types.Int64        `tfsdk:"segment_priority"`
+	FeatureSegment    types.Int64        `tfsdk:"feature_segment_id"`
+	FeatureVersion    types.String       `tfsdk:"feature_version_uuid"`
+}
+
+func (s *SegmentOverrideResourceData) ToClientFS() *flagsmithapi.FeatureState {
+	segment := s.Segment.ValueInt64()
+	fs := flagsmithapi.FeatureState{
+		ID:                s.ID.ValueInt64(),
+		UUID:              s.UUID.ValueString(),
+		Enabled:           s.Enabled.ValueBool(),
+		FeatureStateValue: s.FeatureStateValue.ToClientFSV(),
+		Feature:           s.Feature.ValueInt64(),
+		EnvironmentKey:    s.EnvironmentKey.ValueString(),
+		Segment:           &segment,
+	}
+	if !s.FeatureSegment.IsNull() && !s.FeatureSegment.IsUnknown() {
+		featureSegment := s.FeatureSegment.ValueInt64()
+		fs.FeatureSegment = &featureSegment
+	}
+	if !s.SegmentPriority.IsNull() && !s.SegmentPriority.IsUnknown() {
+		segmentPriority := s.SegmentPriority.ValueInt64()
+		fs.SegmentPriority = &segmentPriority
+	}
+	if !s.Environment.IsNull() && !s.Environment.IsUnknown() {
+		environment := s.Environment.ValueInt64()
+		fs.Environment = &environment
+	}
+	return &fs
+}
+
+// Generate a new SegmentOverrideResourceData from client `FeatureState`
+func MakeSegmentOverrideResourceDataFromClientFS(clientFS *flagsmithapi.FeatureState) SegmentOverrideResourceData {
+	fsValue := MakeFeatureStateValueFromClientFSV(clientFS.FeatureStateValue)
+	segmentOverride := SegmentOverrideResourceData{
+		ID:                types.Int64Value(clientFS.ID),
+		UUID:              types.StringValue(clientFS.UUID),
+		Enabled:           types.BoolValue(clientFS.Enabled),
+		FeatureStateValue: &fsValue,
+		Feature:           types.Int64Value(clientFS.Feature),
+		Environment:       types.Int64Value(*clientFS.Environment),
+		EnvironmentKey:    types.StringValue(clientFS.EnvironmentKey),
+		Segment:           types.Int64Null(),
+		SegmentPriority:   types.Int64Null(),
+		FeatureSegment:    types.Int64Null(),
+		FeatureVersion:    types.StringNull(),
+	}
+	if clientFS.FeatureSegment != nil {
+		segmentOverride.FeatureSegment = types.Int64Value(*clientFS.FeatureSegment)
+	}
+	if clientFS.Segment != nil {
+		segmentOverride.Segment = types.Int64Value(*clientFS.Segment)
+	}
+	if clientFS.SegmentPriority != nil {
+		segmentOverride.SegmentPriority = types.Int64Value(*clientFS.SegmentPriority)
+	}
+	return segmentOverride
+}
+
+type SegmentOverrideOrderResourceData struct {
+	ID             types.String  `tfsdk:"id"`
+	EnvironmentKey types.String  `tfsdk:"environment_key"`
+	Environment    types.Int64   `tfsdk:"environment_id"`
+	Feature        types.Int64   `tfsdk:"feature_id"`
+	Segments       []types.Int64 `tfsdk:"segment_ids"`
+}
+
+func (s *SegmentOverrideOrderResourceData) SegmentIDs() []int64 {
+	segmentIDs := make([]int64, 0, len(s.Segments))
+	for _, segment := range s.Segments {
+		segmentIDs = append(segmentIDs, segment.ValueInt64())
+	}
+	return segmentIDs
+}
+
+// Equal reports whether both values resolve to the same typed value
+func (f *FeatureStateValue) Equal(other *FeatureStateValue) bool {
+	if f == nil || other == nil {
+		return f == other
+	}
+	return reflect.DeepEqual(f.ToClientFSV(), other.ToClientFSV())
+}
+
+// Generate a new FeatureStateValue from the `initial_value` of a feature, the
+// same way Flagsmith infers the type of the value of a new feature state
+func MakeFeatureStateValueFromInitialValue(initialValue string) FeatureStateValue {
+	fsValue := FeatureStateValue{
+		Type:                 types.StringValue("unicode"),
+		StringValue:          types.StringValue(initialValue),
+		SensitiveStringValue: types.StringNull(),
+		IntegerValue:         types.Int64Null(),
+		BooleanValue:         types.BoolNull(),
+	}
+	if intValue, err := strconv.ParseInt(initialValue, 10, 64); err == nil {
+		fsValue.Type = types.StringValue("int")
+		fsValue.StringValue = types.StringNull()
+		fsValue.IntegerValue = types.Int64Value(intValue)
+	} else if lowered := strings.ToLower(initialValue); lowered == "true" || lowered == "false" {
+		fsValue.Type = types.StringValue("bool")
+		fsValue.StringValue = types.StringNull()
+		fsValue.BooleanValue = types.BoolValue(lowered == "true")
+	}
+	return fsValue
+}
+
+// InitialFeatureStateValue is the typed initial value of a feature
+type InitialFeatureStateValue struct {
+	Type         types.String `tfsdk:"type"`
+	StringValue  types.String `tfsdk:"string_value"`
+	IntegerValue types.Int64  `tfsdk:"integer_value"`
+	BooleanValue types.Bool   `tfsdk:"boolean_value"`
+}
+
+// FeatureStateValue returns the value as the value of a feature state
+func (v *InitialFeatureStateValue) FeatureStateValue() *FeatureStateValue {
+	return &FeatureStateValue{
+		Type:                 v.Type,
+		StringValue:          v.StringValue,
+		SensitiveStringValue: types.StringNull(),
+		IntegerValue:         v.IntegerValue,
+		BooleanValue:         v.BooleanValue,
+	}
+}
+
+// String returns the value as the `initial_value` of a feature
+func (v *InitialFeatureStateValue) String() string {
+	switch v.Type.ValueString() {
+	case "int":
+		return strconv.FormatInt(v.IntegerValue.ValueInt64(), 10)
+	case "bool":
+		return strconv.FormatBool(v.BooleanValue.ValueBool())
+	}
+	return v.StringValue.ValueString()
+}
+
+// Generate a new InitialFeatureStateValue from the `initial_value` of a feature
+func MakeInitialFeatureStateValue(initialValue string) *InitialFeatureStateValue {
+	fsValue := MakeFeatureStateValueFromInitialValue(initialValue)
+	return &InitialFeatureStateValue{
+		Type:         fsValue.Type,
+		StringValue:  fsValue.StringValue,
+		IntegerValue: fsValue.IntegerValue,
+		BooleanValue: fsValue.BooleanValue,
+	}
+}
+
+type EnvironmentFeatureStateData struct {
+	Enabled           types.Bool         `tfsdk:"enabled"`
+	FeatureStateValue *FeatureStateValue `tfsdk:"feature_state_value"`
+}
+
+func (e *EnvironmentFeatureStateData) Equal(other EnvironmentFeatureStateData) bool {
+	return e.Enabled.Equal(other.Enabled) && e.FeatureStateValue.Equal(other.FeatureStateValue)
+}
+
+// Generate a new EnvironmentFeatureStateData from client `FeatureState`
+func MakeEnvironmentFeatureStateDataFromClientFS(clientFS *flagsmithapi.FeatureState) EnvironmentFeatureStateData {
+	// The environment feature states listing returns a null value as no value at all
+	fsValue := MakeFeatureStateValueFromInitialValue("")
+	if clientFS.FeatureStateValue != nil {
+		fsValue = MakeFeatureStateValueFromClientFSV(clientFS.FeatureStateValue)
+	}
+	return EnvironmentFeatureStateData{
+		Enabled:           types.BoolValue(clientFS.Enabled),
+		FeatureStateValue: &fsValue,
+	}
+}
+
+// Generate the EnvironmentFeatureStateData a new environment gets for the given feature
+func MakeEnvironmentFeatureStateDataFromClientFeature(clientFeature *flagsmithapi.Feature) EnvironmentFeatureStateData {
+	fsValue := MakeFeatureStateValueFromInitialValue(clientFeature.InitialValue)
+	return EnvironmentFeatureStateData{
+		Enabled:           types.BoolValue(clientFeature.DefaultEnabled),
+		FeatureStateValue: &fsValue,
+	}
+}
+
+type EnvironmentFeatureStatesResourceData struct {
+	ID             types.String                           `tfsdk:"id"`
+	EnvironmentKey types.String                           `tfsdk:"environment_key"`
+	Environment    types.Int64                            `tfsdk:"environment_id"`
+	Exclusive      types.Bool                             `tfsdk:"exclusive"`
+	FeatureStates  map[string]EnvironmentFeatureStateData `tfsdk:"feature_states"`
+}
+
 type MultivariateOptionResourceData struct {
 	Type                        types.String `tfsdk:"type"`
 	ID                          types.Int64  `tfsdk:"id"`
@@ -150,10 +432,20 @@ type MultivariateOptionResourceData struct {
 	ProjectID                   types.Int64  `tfsdk:"project_id"`
 	IntegerValue                types.Int64  `tfsdk:"integer_value"`
 	StringValue                 types.String `tfsdk:"string_value"`
+	SensitiveStringValue        types.String `tfsdk:"sensitive_string_value"`
 	BooleanValue                types.Bool   `tfsdk:"boolean_value"`
 	DefaultPercentageAllocation types.Number `tfsdk:"default_percentage_allocation"`
 }
 
+// MarkSensitive moves a string value to `sensitive_string_value`
+func (m *MultivariateOptionResourceData) MarkSensitive() {
+	if m.StringValue.IsNull() {
+		return
+	}
+	m.SensitiveStringValue = m.StringValue
+	m.StringValue = types.StringNull()
+}
+
 func NewMultivariateOptionFromClientOption(clientMvOption *flagsmithapi.FeatureMultivariateOption) MultivariateOptionResourceData {
 	mvOption := MultivariateOptionResourceData{
 		Type:                        types.StringValue(clientMvOption.Type),
@@ -179,6 +471,9 @@ func NewMultivariateOptionFromClientOption(clientMvOption *flagsmithapi.FeatureM
 func (m *MultivariateOptionResourceData) ToClientMultivariateOption() *flagsmithapi.FeatureMultivariateOption {
 	defaultPercentageAllocation, _ := m.DefaultPercentageAllocation.ValueBigFloat().Float64()
 	stringValue := m.StringValue.ValueString()
+	if !m.SensitiveStringValue.IsNull() {
+		stringValue = m.SensitiveStringValue.ValueString()
+	}
 	booleanValue := m.BooleanValue.ValueBool()
 
 	mo := flagsmithapi.FeatureMultivariateOption{
@@ -226,8 +521,53 @@ type FeatureResourceData struct {
 	Tags           *[]types.Int64 `tfsdk:"tags"`
 	ProjectID      types.Int64    `tfsdk:"project_id"`
 	ProjectUUID    types.String   `tfsdk:"project_uuid"`
+	DeletionMode   types.String   `tfsdk:"deletion_mode"`
+
+	InitialFeatureStateValue *InitialFeatureStateValue `tfsdk:"initial_feature_state_value"`
+
+	OwnerEmails     *[]types.String `tfsdk:"owner_emails"`
+	GroupOwnerNames *[]types.String `tfsdk:"group_owner_names"`
+	TagNames        *[]types.String `tfsdk:"tag_names"`
+
+	CreateMissingTags types.Bool `tfsdk:"create_missing_tags"`
+
+	TagsAuthoritative   types.Bool `tfsdk:"tags_authoritative"`
+	OwnersAuthoritative types.Bool `tfsdk:"owners_authoritative"`
+
+	// Metadata holds the metadata keyed by field name, nil if not managed
+	Metadata map[string]types.String `tfsdk:"metadata"`
+
+	// EnvironmentValues holds the managed feature states, keyed by environment key
+	EnvironmentValues map[string]EnvironmentFeatureStateData `tfsdk:"environment_values"`
+
+	IncludeEnvironmentStates types.Bool `tfsdk:"include_environment_states"`
+	// EnvironmentStates holds FeatureEnvironmentStateData keyed by environment
+	// name, a types.Map since it is unknown in the plans of updates
+	EnvironmentStates types.Map `tfsdk:"environment_states"`
+}
+
+// FeatureEnvironmentStateData is the feature state of a feature in one of the
+// environments of its project
+type FeatureEnvironmentStateData struct {
+	Enabled              types.Bool         `tfsdk:"enabled"`
+	FeatureStateValue    *FeatureStateValue `tfsdk:"feature_state_value"`
+	SegmentOverrideCount types.Int64        `tfsdk:"segment_override_count"`
 }
 
+// featureEnvironmentStateType is the type of the elements of
+// FeatureResourceData.EnvironmentStates
+var featureEnvironmentStateType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"enabled": types.BoolType,
+	"feature_state_value": types.ObjectType{AttrTypes: map[string]attr.Type{
+		"type":                   types.StringType,
+		"string_value":           types.StringType,
+		"sensitive_string_value": types.StringType,
+		"integer_value":          types.Int64Type,
+		"boolean_value":          types.BoolType,
+	}},
+	"segment_override_count": types.Int64Type,
+}}
+
 func (f *FeatureResourceData) ToClientFeature() *flagsmithapi.Feature {
 	typeValue := f.Type.ValueString()
 	if typeValue == "" {
@@ -298,6 +638,10 @@ func MakeFeatureResourceDataFromClientFeature(clientFeature *flagsmithapi.Featur
 		ProjectUUID:    types.StringValue(clientFeature.ProjectUUID),
 		Owners:         &[]types.Int64{},
 		GroupOwners:    &[]types.Int64{},
+
+		InitialFeatureStateValue: MakeInitialFeatureStateValue(clientFeature.InitialValue),
+
+		EnvironmentStates: types.MapNull(featureEnvironmentStateType),
 	}
 	if clientFeature.Description != nil {
 		resourceData.Description = types.StringValue(*clientFeature.Description)
@@ -424,6 +768,9 @@ type SegmentResourceData struct {
 	ProjectUUID types.String `tfsdk:"project_uuid"`
 	FeatureID   types.Int64  `tfsdk:"feature_id"`
 	Rules       []Rule       `tfsdk:"rules"`
+
+	// Metadata holds the metadata keyed by field name, nil if not managed
+	Metadata map[string]types.String `tfsdk:"metadata"`
 }
 
 func (s *SegmentResourceData) ToClientSegment() *flagsmithapi.Segment {
@@ -623,7 +970,10 @@ type EnvironmentResourceData struct {
 	HideSensitiveData types.Bool `tfsdk:"hide_sensitive_data"`
 	UseIdentityCompositeKeyForHashing types.Bool `tfsdk:"use_identity_composite_key_for_hashing"`
 	MinimumChangeRequestApprovals types.Int64 `tfsdk:"minimum_change_request_approvals"`
+	UseV2FeatureVersioning types.Bool `tfsdk:"use_v2_feature_versioning"`
 
+	// Metadata holds the metadata keyed by field name, nil if not managed
+	Metadata map[string]types.String `tfsdk:"metadata"`
 }
 
 func (e *EnvironmentResourceData) ToClientEnvironment() *flagsmithapi.Environment {
@@ -659,6 +1009,7 @@ func MakeEnvironmentResourceDataFromClientEnvironment(clientEnvironment *flagsmi
 		HideDisabledFlags: types.BoolValue(clientEnvironment.HideDisabledFlags),
 		HideSensitiveData: types.BoolValue(clientEnvironment.HideSensitiveData),
 		UseIdentityCompositeKeyForHashing: types.BoolValue(clientEnvironment.UseIdentityCompositeKeyForHashing),
+		UseV2FeatureVersioning: types.BoolValue(false),
 	}
 	if clientEnvironment.Description != "" {
 		resourceData.Description = types.StringValue(clientEnvironment.Description)
@@ -692,3 +1043,353 @@ func MakeUserResourceDataFromClientUser(clientUser *flagsmithapi.User, orgID int
 		Role:           types.StringValue(clientUser.Role),
 	}
 }
+
+type IdentityOverrideResourceData struct {
+	ID                types.String       `tfsdk:"id"`
+	EnvironmentKey    types.String       `tfsdk:"environment_key"`
+	Identifier        types.String       `tfsdk:"identifier"`
+	Identity          types.String       `tfsdk:"identity_id"`
+	Feature           types.Int64        `tfsdk:"feature_id"`
+	Enabled           types.Bool         `tfsdk:"enabled"`
+	FeatureStateValue *FeatureStateValue `tfsdk:"feature_state_value"`
+	EdgeIdentities    types.Bool         `tfsdk:"edge_identities"`
+}
+
+func (i *IdentityOverrideResourceData) ToIdentityOverride() *IdentityOverride {
+	return &IdentityOverride{
+		ID:                i.ID.ValueString(),
+		Feature:           i.Feature.ValueInt64(),
+		Enabled:           i.Enabled.ValueBool(),
+		FeatureStateValue: i.FeatureStateValue.ToClientFSV(),
+	}
+}
+
+// Generate a new IdentityOverrideResourceData from an `IdentityOverride`, the
+// identity related fields are left for the caller to set
+func MakeIdentityOverrideResourceData(override *IdentityOverride) IdentityOverrideResourceData {
+	fsValue := MakeFeatureStateValueFromClientFSV(override.FeatureStateValue)
+	return IdentityOverrideResourceData{
+		ID:                types.StringValue(override.ID),
+		Feature:           types.Int64Value(override.Feature),
+		Enabled:           types.BoolValue(override.Enabled),
+		FeatureStateValue: &fsValue,
+	}
+}
+
+type TraitValue struct {
+	StringValue  types.String  `tfsdk:"string_value"`
+	IntegerValue types.Int64   `tfsdk:"integer_value"`
+	FloatValue   types.Float64 `tfsdk:"float_value"`
+	BooleanValue types.Bool    `tfsdk:"boolean_value"`
+}
+
+func (t *TraitValue) ToClientTrait(key string) *flagsmithapi.Trait {
+	trait := flagsmithapi.Trait{TraitKey: key}
+	switch {
+	case !t.IntegerValue.IsNull():
+		value := int(t.IntegerValue.ValueInt64())
+		trait.ValueType = "int"
+		trait.IntegerValue = &value
+	case !t.FloatValue.IsNull():
+		value := t.FloatValue.ValueFloat64()
+		trait.ValueType = "float"
+		trait.FloatValue = &value
+	case !t.BooleanValue.IsNull():
+		value := t.BooleanValue.ValueBool()
+		trait.ValueType = "bool"
+		trait.BooleanValue = &value
+	default:
+		value := t.StringValue.ValueString()
+		trait.ValueType = "unicode"
+		trait.StringValue = &value
+	}
+	return &trait
+}
+
+func (t TraitValue) Equal(other TraitValue) bool {
+	return t.StringValue.Equal(other.StringValue) && t.IntegerValue.Equal(other.IntegerValue) &&
+		t.FloatValue.Equal(other.FloatValue) && t.BooleanValue.Equal(other.BooleanValue)
+}
+
+// Generate a new TraitValue from client `Trait`. A whole number is kept as a
+// float if prior, the value it replaces, is a float.
+func MakeTraitValueFromClientTrait(trait *flagsmithapi.Trait, prior *TraitValue) TraitValue {
+	traitValue := TraitValue{
+		StringValue:  types.StringNull(),
+		IntegerValue: types.Int64Null(),
+		FloatValue:   types.Float64Null(),
+		BooleanValue: types.BoolNull(),
+	}
+	switch {
+	case trait.ValueType == "int" && trait.IntegerValue != nil:
+		if prior != nil && !prior.FloatValue.IsNull() {
+			traitValue.FloatValue = types.Float64Value(float64(*trait.IntegerValue))
+		} else {
+			traitValue.IntegerValue = types.Int64Value(int64(*trait.IntegerValue))
+		}
+	case trait.ValueType == "float" && trait.FloatValue != nil:
+		traitValue.FloatValue = types.Float64Value(*trait.FloatValue)
+	case trait.ValueType == "bool" && trait.BooleanValue != nil:
+		traitValue.BooleanValue = types.BoolValue(*trait.BooleanValue)
+	case trait.StringValue != nil:
+		traitValue.StringValue = types.StringValue(*trait.StringValue)
+	default:
+		traitValue.StringValue = types.StringValue("")
+	}
+	return traitValue
+}
+
+type IdentityResourceData struct {
+	ID             types.String `tfsdk:"id"`
+	EnvironmentKey types.String `tfsdk:"environment_key"`
+	Identifier     types.String `tfsdk:"identifier"`
+	EdgeIdentities types.Bool   `tfsdk:"edge_identities"`
+	Traits         types.Map    `tfsdk:"traits"`
+}
+
+type IdentityFlagData struct {
+	FeatureID         types.Int64        `tfsdk:"feature_id"`
+	Enabled           types.Bool         `tfsdk:"enabled"`
+	FeatureStateValue *FeatureStateValue `tfsdk:"feature_state_value"`
+}
+
+type IdentityDataSourceData struct {
+	ID             types.String                `tfsdk:"id"`
+	EnvironmentKey types.String                `tfsdk:"environment_key"`
+	Identifier     types.String                `tfsdk:"identifier"`
+	EdgeIdentities types.Bool                  `tfsdk:"edge_identities"`
+	Traits         map[string]TraitValue       `tfsdk:"traits"`
+	Flags          map[string]IdentityFlagData `tfsdk:"flags"`
+}
+
+type EnvironmentPromotionResourceData struct {
+	ID                         types.String `tfsdk:"id"`
+	SourceEnvironmentKey       types.String `tfsdk:"source_environment_key"`
+	TargetEnvironmentKey       types.String `tfsdk:"target_environment_key"`
+	FeatureIDs                 types.Set    `tfsdk:"feature_ids"`
+	Tags                       types.Set    `tfsdk:"tags"`
+	IncludeSegmentOverrides    types.Bool   `tfsdk:"include_segment_overrides"`
+	IncludeMultivariateWeights types.Bool   `tfsdk:"include_multivariate_weights"`
+	Triggers                   types.Map    `tfsdk:"triggers"`
+	InSync                     types.Bool   `tfsdk:"in_sync"`
+	Changes                    types.List   `tfsdk:"changes"`
+}
+
+// EnvironmentPromotionChangeData is a change made to the target environment
+// of a promotion to match the source environment
+type EnvironmentPromotionChangeData struct {
+	FeatureID           types.Int64              `tfsdk:"feature_id"`
+	FeatureName         types.String             `tfsdk:"feature_name"`
+	Segment             types.Int64              `tfsdk:"segment_id"`
+	Action              types.String             `tfsdk:"action"`
+	Enabled             types.Bool               `tfsdk:"enabled"`
+	FeatureStateValue   types.String             `tfsdk:"feature_state_value"`
+	Priority            types.Int64              `tfsdk:"priority"`
+	MultivariateWeights map[string]types.Float64 `tfsdk:"multivariate_weights"`
+}
+
+type EnvironmentDiffData struct {
+	SourceEnvironmentKey types.String                          `tfsdk:"source_environment_key"`
+	TargetEnvironmentKey types.String                          `tfsdk:"target_environment_key"`
+	Identical            types.Bool                            `tfsdk:"identical"`
+	Features             map[string]EnvironmentDiffFeatureData `tfsdk:"features"`
+}
+
+type EnvironmentDiffFeatureData struct {
+	FeatureID types.Int64                      `tfsdk:"feature_id"`
+	Identical types.Bool                       `tfsdk:"identical"`
+	Source    *EnvironmentDiffFeatureStateData `tfsdk:"source"`
+	Target    *EnvironmentDiffFeatureStateData `tfsdk:"target"`
+}
+
+// EnvironmentDiffFeatureStateData is how a feature is served by one of the
+// compared environments, its environment default along with its segment overrides
+type EnvironmentDiffFeatureStateData struct {
+	Enabled             types.Bool                           `tfsdk:"enabled"`
+	FeatureStateValue   *FeatureStateValue                   `tfsdk:"feature_state_value"`
+	MultivariateWeights map[string]types.Float64             `tfsdk:"multivariate_weights"`
+	SegmentOverrides    []EnvironmentDiffSegmentOverrideData `tfsdk:"segment_overrides"`
+}
+
+type EnvironmentDiffSegmentOverrideData struct {
+	Segment             types.Int64              `tfsdk:"segment_id"`
+	Priority            types.Int64              `tfsdk:"priority"`
+	Enabled             types.Bool               `tfsdk:"enabled"`
+	FeatureStateValue   *FeatureStateValue       `tfsdk:"feature_state_value"`
+	MultivariateWeights map[string]types.Float64 `tfsdk:"multivariate_weights"`
+}
+
+type FeatureStateDataSourceData struct {
+	ID                types.Int64        `tfsdk:"id"`
+	UUID              types.String       `tfsdk:"uuid"`
+	Enabled           types.Bool         `tfsdk:"enabled"`
+	Value             types.Dynamic      `tfsdk:"value"`
+	FeatureStateValue *FeatureStateValue `tfsdk:"feature_state_value"`
+	Feature           types.Int64        `tfsdk:"feature_id"`
+	FeatureUUID       types.String       `tfsdk:"feature_uuid"`
+	FeatureName       types.String       `tfsdk:"feature_name"`
+	Environment       types.Int64        `tfsdk:"environment_id"`
+	EnvironmentKey    types.String       `tfsdk:"environment_key"`
+	EnvironmentUUID   types.String       `tfsdk:"environment_uuid"`
+	Segment           types.Int64        `tfsdk:"segment_id"`
+	SegmentPriority   types.Int64        `tfsdk:"segment_priority"`
+	FeatureSegment    types.Int64        `tfsdk:"feature_segment_id"`
+	UpdatedAt         types.String       `tfsdk:"updated_at"`
+}
+
+// Generate a new FeatureStateDataSourceData from client `FeatureState`, `value`
+// being the feature state value as a plain string, number or bool
+func MakeFeatureStateDataSourceDataFromClientFS(clientFS *flagsmithapi.FeatureState) FeatureStateDataSourceData {
+	resourceData := MakeFeatureStateResourceDataFromClientFS(clientFS)
+	value := types.DynamicValue(resourceData.FeatureStateValue.StringValue)
+	switch resourceData.FeatureStateValue.Type.ValueString() {
+	case "int":
+		value = types.DynamicValue(resourceData.FeatureStateValue.IntegerValue)
+	case "bool":
+		value = types.DynamicValue(resourceData.FeatureStateValue.BooleanValue)
+	}
+	return FeatureStateDataSourceData{
+		ID:                resourceData.ID,
+		UUID:              resourceData.UUID,
+		Enabled:           resourceData.Enabled,
+		Value:             value,
+		FeatureStateValue: resourceData.FeatureStateValue,
+		Feature:           resourceData.Feature,
+		FeatureUUID:       types.StringNull(),
+		FeatureName:       types.StringNull(),
+		Environment:       resourceData.Environment,
+		EnvironmentKey:    resourceData.EnvironmentKey,
+		EnvironmentUUID:   types.StringNull(),
+		Segment:           resourceData.Segment,
+		SegmentPriority:   resourceData.SegmentPriority,
+		FeatureSegment:    resourceData.FeatureSegment,
+		UpdatedAt:         types.StringNull(),
+	}
+}
+
+type MetadataFieldResourceData struct {
+	ID             types.Int64  `tfsdk:"id"`
+	OrganisationID types.Int64  `tfsdk:"organisation_id"`
+	Name           types.String `tfsdk:"name"`
+	Type           types.String `tfsdk:"type"`
+	Description    types.String `tfsdk:"description"`
+}
+
+func (m *MetadataFieldResourceData) ToClientMetadataField() *MetadataField {
+	field := MetadataField{
+		Name:         m.Name.ValueString(),
+		Type:         m.Type.ValueString(),
+		Description:  m.Description.ValueString(),
+		Organisation: m.OrganisationID.ValueInt64(),
+	}
+	if !m.ID.IsNull() && !m.ID.IsUnknown() {
+		fieldID := m.ID.ValueInt64()
+		field.ID = &fieldID
+	}
+	return &field
+}
+
+func MakeMetadataFieldResourceDataFromClientField(clientField *MetadataField) MetadataFieldResourceData {
+	resourceData := MetadataFieldResourceData{
+		ID:             types.Int64Value(*clientField.ID),
+		OrganisationID: types.Int64Value(clientField.Organisation),
+		Name:           types.StringValue(clientField.Name),
+		Type:           types.StringValue(clientField.Type),
+	}
+	// Flagsmith returns an empty description for the fields that have none
+	if clientField.Description != "" {
+		resourceData.Description = types.StringValue(clientField.Description)
+	}
+	return resourceData
+}
+
+type MetadataModelFieldResourceData struct {
+	ID                    types.Int64    `tfsdk:"id"`
+	OrganisationID        types.Int64    `tfsdk:"organisation_id"`
+	FieldID               types.Int64    `tfsdk:"field_id"`
+	ContentType           types.String   `tfsdk:"content_type"`
+	RequiredForProjectIDs *[]types.Int64 `tfsdk:"required_for_project_ids"`
+}
+
+// ToClientMetadataModelField returns the metadata model field, given the IDs
+// of the content types of its model and of projects
+func (m *MetadataModelFieldResourceData) ToClientMetadataModelField(contentType, projectContentType int64) *MetadataModelField {
+	modelField := MetadataModelField{
+		Field:         m.FieldID.ValueInt64(),
+		ContentType:   contentType,
+		IsRequiredFor: []MetadataModelFieldRequirement{},
+	}
+	if !m.ID.IsNull() && !m.ID.IsUnknown() {
+		modelFieldID := m.ID.ValueInt64()
+		modelField.ID = &modelFieldID
+	}
+	if m.RequiredForProjectIDs != nil {
+		for _, projectID := range *m.RequiredForProjectIDs {
+			modelField.IsRequiredFor = append(modelField.IsRequiredFor, MetadataModelFieldRequirement{
+				ContentType: projectContentType,
+				ObjectID:    projectID.ValueInt64(),
+			})
+		}
+	}
+	return &modelField
+}
+
+// Generate a new MetadataModelFieldResourceData from client
+// `MetadataModelField`, model being the model of its content type
+func MakeMetadataModelFieldResourceDataFromClientModelField(clientModelField *MetadataModelField, orgID int64, model string, projectContentType int64) MetadataModelFieldResourceData {
+	resourceData := MetadataModelFieldResourceData{
+		ID:             types.Int64Value(*clientModelField.ID),
+		OrganisationID: types.Int64Value(orgID),
+		FieldID:        types.Int64Value(clientModelField.Field),
+		ContentType:    types.StringValue(model),
+	}
+	requiredFor := []types.Int64{}
+	for _, requirement := range clientModelField.IsRequiredFor {
+		if requirement.ContentType == projectContentType {
+			requiredFor = append(requiredFor, types.Int64Value(requirement.ObjectID))
+		}
+	}
+	if len(requiredFor) > 0 {
+		resourceData.RequiredForProjectIDs = &requiredFor
+	}
+	return resourceData
+}
+
+type FeatureTagResourceData struct {
+	ID          types.String `tfsdk:"id"`
+	FeatureUUID types.String `tfsdk:"feature_uuid"`
+	TagID       types.Int64  `tfsdk:"tag_id"`
+}
+
+// Generate a new FeatureTagResourceData for the tag attached to the feature
+func MakeFeatureTagResourceData(featureUUID string, tagID int64) FeatureTagResourceData {
+	return FeatureTagResourceData{
+		ID:          types.StringValue(featureUUID + "," + strconv.FormatInt(tagID, 10)),
+		FeatureUUID: types.StringValue(featureUUID),
+		TagID:       types.Int64Value(tagID),
+	}
+}
+
+type FeatureOwnerResourceData struct {
+	ID          types.String `tfsdk:"id"`
+	FeatureUUID types.String `tfsdk:"feature_uuid"`
+	UserID      types.Int64  `tfsdk:"user_id"`
+	GroupID     types.Int64  `tfsdk:"group_id"`
+}
+
+// Generate a new FeatureOwnerResourceData for the user or group owning the
+// feature, ownerType being "user" or "group"
+func MakeFeatureOwnerResourceData(featureUUID, ownerType string, ownerID int64) FeatureOwnerResourceData {
+	resourceData := FeatureOwnerResourceData{
+		ID:          types.StringValue(featureUUID + "," + ownerType + "," + strconv.FormatInt(ownerID, 10)),
+		FeatureUUID: types.StringValue(featureUUID),
+		UserID:      types.Int64Null(),
+		GroupID:     types.Int64Null(),
+	}
+	if ownerType == featureOwnerTypeGroup {
+		resourceData.GroupID = types.Int64Value(ownerID)
+	} else {
+		resourceData.UserID = types.Int64Value(ownerID)
+	}
+	return resourceData
+}
@@ -1,9 +1,15 @@
 package flagsmith
 
 import (
+	"context"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 
 	flagsmithapi "github.com/Flagsmith/flagsmith-go-api-client"
 	"github.com/stretchr/testify/assert"
@@ -337,3 +343,374 @@ func TestFeatureStateResourceDataToClientFS(t *testing.T) {
 	assert.Equal(t, nilBool, clientFS.FeatureStateValue.BooleanValue)
 
 }
+
+func TestMakeSegmentOverrideResourceDataFromClientFS(t *testing.T) {
+	// Given
+	stringValue := "override"
+	environmentID := int64(1)
+	featureSegmentID := int64(11)
+	segmentID := int64(101)
+	segmentPriority := int64(2)
+	clientFS := flagsmithapi.FeatureState{
+		ID:   1,
+		UUID: "1a1f9371-6181-4035-93f5-09bd291b7d5e",
+		FeatureStateValue: &flagsmithapi.FeatureStateValue{
+			Type:        "unicode",
+			StringValue: &stringValue,
+		},
+		Enabled:         true,
+		Feature:         int64(1),
+		Environment:     &environmentID,
+		FeatureSegment:  &featureSegmentID,
+		Segment:         &segmentID,
+		SegmentPriority: &segmentPriority,
+	}
+
+	// When
+	segmentOverride := MakeSegmentOverrideResourceDataFromClientFS(&clientFS)
+
+	// Then
+	assert.Equal(t, int64(1), segmentOverride.ID.ValueInt64())
+	assert.Equal(t, true, segmentOverride.Enabled.ValueBool())
+	assert.Equal(t, featureSegmentID, segmentOverride.FeatureSegment.ValueInt64())
+	assert.Equal(t, segmentID, segmentOverride.Segment.ValueInt64())
+	assert.Equal(t, segmentPriority, segmentOverride.SegmentPriority.ValueInt64())
+	assert.Equal(t, stringValue, segmentOverride.FeatureStateValue.StringValue.ValueString())
+
+	// And the conversion back keeps the segment binding
+	roundTripFS := segmentOverride.ToClientFS()
+	assert.Equal(t, featureSegmentID, *roundTripFS.FeatureSegment)
+	assert.Equal(t, segmentID, *roundTripFS.Segment)
+	assert.Equal(t, segmentPriority, *roundTripFS.SegmentPriority)
+}
+
+func TestMakeFeatureStateValueFromInitialValue(t *testing.T) {
+	// When
+	intFSV := MakeFeatureStateValueFromInitialValue("10")
+	boolFSV := MakeFeatureStateValueFromInitialValue("True")
+	stringFSV := MakeFeatureStateValueFromInitialValue("some_value")
+	emptyFSV := MakeFeatureStateValueFromInitialValue("")
+
+	// Then
+	assert.Equal(t, "int", intFSV.Type.ValueString())
+	assert.Equal(t, int64(10), intFSV.IntegerValue.ValueInt64())
+	assert.True(t, intFSV.StringValue.IsNull())
+
+	assert.Equal(t, "bool", boolFSV.Type.ValueString())
+	assert.Equal(t, true, boolFSV.BooleanValue.ValueBool())
+
+	assert.Equal(t, "unicode", stringFSV.Type.ValueString())
+	assert.Equal(t, "some_value", stringFSV.StringValue.ValueString())
+
+	assert.Equal(t, "unicode", emptyFSV.Type.ValueString())
+	assert.Equal(t, "", emptyFSV.StringValue.ValueString())
+}
+
+func TestEnvironmentFeatureStateDataEqual(t *testing.T) {
+	// Given
+	feature := flagsmithapi.Feature{Name: "feature", InitialValue: "10", DefaultEnabled: true}
+	intValue := int64(10)
+	clientFS := flagsmithapi.FeatureState{
+		Enabled:           true,
+		FeatureStateValue: &flagsmithapi.FeatureStateValue{Type: "int", IntegerValue: &intValue},
+	}
+	nullValueFS := flagsmithapi.FeatureState{Enabled: true}
+
+	// When
+	fromFeature := MakeEnvironmentFeatureStateDataFromClientFeature(&feature)
+	fromFS := MakeEnvironmentFeatureStateDataFromClientFS(&clientFS)
+	fromNullValueFS := MakeEnvironmentFeatureStateDataFromClientFS(&nullValueFS)
+
+	// Then
+	assert.True(t, fromFeature.Equal(fromFS))
+	assert.False(t, fromFeature.Equal(fromNullValueFS))
+	assert.Equal(t, "", fromNullValueFS.FeatureStateValue.StringValue.ValueString())
+}
+
+func TestFeatureStateResourceDataManages(t *testing.T) {
+	// Given
+	allFields := FeatureStateResourceData{ManagedFields: types.SetNull(types.StringType)}
+	enabledOnly := FeatureStateResourceData{
+		ManagedFields: types.SetValueMust(types.StringType, []attr.Value{types.StringValue(ManagedFieldEnabled)}),
+	}
+
+	// Then
+	assert.True(t, allFields.Manages(ManagedFieldEnabled))
+	assert.True(t, allFields.Manages(ManagedFieldValue))
+	assert.True(t, enabledOnly.Manages(ManagedFieldEnabled))
+	assert.False(t, enabledOnly.Manages(ManagedFieldValue))
+}
+
+func TestFeatureStateResourceDataCopyUnmanagedFields(t *testing.T) {
+	// Given
+	data := FeatureStateResourceData{
+		Enabled:           types.BoolValue(true),
+		FeatureStateValue: &FeatureStateValue{Type: types.StringValue("unicode"), StringValue: types.StringValue("configured")},
+		ManagedFields:     types.SetValueMust(types.StringType, []attr.Value{types.StringValue(ManagedFieldEnabled)}),
+	}
+	other := FeatureStateResourceData{
+		Enabled:           types.BoolValue(false),
+		FeatureStateValue: &FeatureStateValue{Type: types.StringValue("unicode"), StringValue: types.StringValue("live")},
+	}
+
+	// When
+	data.CopyUnmanagedFields(&other)
+
+	// Then
+	assert.True(t, data.Enabled.ValueBool())
+	assert.Equal(t, "live", data.FeatureStateValue.StringValue.ValueString())
+}
+
+func TestSensitiveFeatureStateValueToClientFSV(t *testing.T) {
+	// Given
+	clientFSV := flagsmithapi.FeatureStateValue{Type: "unicode", StringValue: func() *string { s := "secret"; return &s }()}
+	fsValue := MakeFeatureStateValueFromClientFSV(&clientFSV)
+
+	// When
+	fsValue.MarkSensitive()
+
+	// Then
+	assert.True(t, fsValue.IsSensitive())
+	assert.True(t, fsValue.StringValue.IsNull())
+	assert.Equal(t, "secret", fsValue.SensitiveStringValue.ValueString())
+	assert.Equal(t, &clientFSV, fsValue.ToClientFSV())
+}
+
+func TestMultivariateOptionMarkSensitive(t *testing.T) {
+	// Given
+	value := "secret"
+	featureID := int64(1)
+	projectID := int64(2)
+	mvOption := NewMultivariateOptionFromClientOption(&flagsmithapi.FeatureMultivariateOption{
+		Type:        "unicode",
+		StringValue: &value,
+		FeatureID:   &featureID,
+		ProjectID:   &projectID,
+	})
+
+	// When
+	mvOption.MarkSensitive()
+
+	// Then
+	assert.True(t, mvOption.StringValue.IsNull())
+	assert.Equal(t, "secret", mvOption.SensitiveStringValue.ValueString())
+	assert.Equal(t, "secret", *mvOption.ToClientMultivariateOption().StringValue)
+}
+
+func TestMakeTraitValueFromClientTraitKeepsFloat(t *testing.T) {
+	// Given
+	integerValue := 3
+	trait := flagsmithapi.Trait{TraitKey: "score", ValueType: "int", IntegerValue: &integerValue}
+	prior := TraitValue{FloatValue: types.Float64Value(3)}
+
+	// When
+	traitValue := MakeTraitValueFromClientTrait(&trait, &prior)
+	withoutPrior := MakeTraitValueFromClientTrait(&trait, nil)
+
+	// Then
+	assert.True(t, traitValue.IntegerValue.IsNull())
+	assert.Equal(t, float64(3), traitValue.FloatValue.ValueFloat64())
+	assert.Equal(t, int64(3), withoutPrior.IntegerValue.ValueInt64())
+	assert.Equal(t, "float", traitValue.ToClientTrait("score").ValueType)
+}
+
+func TestMakeFeatureStateDataSourceDataFromClientFS(t *testing.T) {
+	// Given
+	environment := int64(3)
+	value := int64(42)
+	clientFS := flagsmithapi.FeatureState{
+		ID:                1,
+		UUID:              "fs-uuid",
+		Enabled:           true,
+		Feature:           2,
+		Environment:       &environment,
+		EnvironmentKey:    "env_key",
+		FeatureStateValue: &flagsmithapi.FeatureStateValue{Type: "int", IntegerValue: &value},
+	}
+
+	// When
+	data := MakeFeatureStateDataSourceDataFromClientFS(&clientFS)
+
+	// Then
+	assert.Equal(t, types.DynamicValue(types.Int64Value(42)), data.Value)
+	assert.Equal(t, int64(42), data.FeatureStateValue.IntegerValue.ValueInt64())
+	assert.Equal(t, "env_key", data.EnvironmentKey.ValueString())
+	assert.True(t, data.Segment.IsNull())
+	assert.True(t, data.SegmentPriority.IsNull())
+}
+
+func TestFeatureEnvironmentStateTypeMatchesData(t *testing.T) {
+	// Given
+	value := MakeFeatureStateValueFromClientFSV(&flagsmithapi.FeatureStateValue{Type: "unicode", StringValue: &[]string{"secret"}[0]})
+	value.MarkSensitive()
+	states := map[string]FeatureEnvironmentStateData{
+		"Production": {
+			Enabled:              types.BoolValue(true),
+			FeatureStateValue:    &value,
+			SegmentOverrideCount: types.Int64Value(2),
+		},
+	}
+
+	// When
+	environmentStates, diags := types.MapValueFrom(context.Background(), featureEnvironmentStateType, states)
+
+	// Then
+	assert.False(t, diags.HasError())
+	assert.Len(t, environmentStates.Elements(), 1)
+}
+
+func TestInitialFeatureStateValueRoundTrip(t *testing.T) {
+	for initialValue, expectedType := range map[string]string{"10": "int", "true": "bool", "dark": "unicode", "": "unicode"} {
+		// Given
+		value := MakeInitialFeatureStateValue(initialValue)
+
+		expected := MakeFeatureStateValueFromInitialValue(initialValue)
+
+		// When
+		fsValue := value.FeatureStateValue()
+
+		// Then
+		assert.Equal(t, expectedType, value.Type.ValueString())
+		assert.Equal(t, initialValue, value.String())
+		assert.True(t, fsValue.Equal(&expected))
+	}
+}
+
+func TestUpgradeFeatureStateV0(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	r := &featureResource{}
+	upgrader := r.UpgradeState(ctx)[0]
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	priorType := upgrader.PriorSchema.Type().TerraformType(ctx).(tftypes.Object)
+	priorValues := map[string]tftypes.Value{}
+	for name, attributeType := range priorType.AttributeTypes {
+		priorValues[name] = tftypes.NewValue(attributeType, nil)
+	}
+	priorValues["uuid"] = tftypes.NewValue(tftypes.String, "feature-uuid")
+	priorValues["initial_value"] = tftypes.NewValue(tftypes.String, "10")
+	req := resource.UpgradeStateRequest{State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: tftypes.NewValue(priorType, priorValues)}}
+	resp := resource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+
+	// When
+	upgrader.StateUpgrader(ctx, req, &resp)
+
+	// Then
+	assert.False(t, resp.Diagnostics.HasError())
+	var uuid types.String
+	var value *InitialFeatureStateValue
+	resp.State.GetAttribute(ctx, path.Root("uuid"), &uuid)
+	resp.State.GetAttribute(ctx, path.Root("initial_feature_state_value"), &value)
+	assert.Equal(t, "feature-uuid", uuid.ValueString())
+	assert.Equal(t, "int", value.Type.ValueString())
+	assert.Equal(t, int64(10), value.IntegerValue.ValueInt64())
+	assert.True(t, value.StringValue.IsNull())
+	var deletionMode types.String
+	var tagsAuthoritative types.Bool
+	resp.State.GetAttribute(ctx, path.Root("deletion_mode"), &deletionMode)
+	resp.State.GetAttribute(ctx, path.Root("tags_authoritative"), &tagsAuthoritative)
+	assert.Equal(t, FeatureDeletionModeDelete, deletionMode.ValueString())
+	assert.True(t, tagsAuthoritative.ValueBool())
+}
+
+func TestKeepManagedReferences(t *testing.T) {
+	// Given
+	data := FeatureResourceData{
+		Tags:                &[]types.Int64{types.Int64Value(1), types.Int64Value(2)},
+		Owners:              &[]types.Int64{types.Int64Value(3), types.Int64Value(4)},
+		GroupOwners:         &[]types.Int64{types.Int64Value(5)},
+		TagsAuthoritative:   types.BoolValue(false),
+		OwnersAuthoritative: types.BoolValue(true),
+	}
+	managed := FeatureResourceData{
+		Tags: &[]types.Int64{types.Int64Value(2), types.Int64Value(6)},
+	}
+
+	// When
+	keepManagedReferences(&data, &managed)
+
+	// Then
+	assert.Equal(t, &[]types.Int64{types.Int64Value(2)}, data.Tags)
+	assert.Nil(t, data.TagNames)
+	assert.Equal(t, &[]types.Int64{types.Int64Value(3), types.Int64Value(4)}, data.Owners)
+	assert.Equal(t, &[]types.Int64{types.Int64Value(5)}, data.GroupOwners)
+
+	// When
+	data.OwnersAuthoritative = types.BoolValue(false)
+	keepManagedReferences(&data, &managed)
+
+	// Then
+	assert.Nil(t, data.Owners)
+	assert.Nil(t, data.GroupOwners)
+}
+
+func TestPlanUnmanagedFields(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	(&featureStateResource{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	valueType := objectType.AttributeTypes["feature_state_value"].(tftypes.Object)
+	makeValue := func(enabled interface{}) tftypes.Value {
+		values := map[string]tftypes.Value{}
+		for name, attributeType := range objectType.AttributeTypes {
+			values[name] = tftypes.NewValue(attributeType, nil)
+		}
+		values["enabled"] = tftypes.NewValue(tftypes.Bool, enabled)
+		values["feature_state_value"] = tftypes.NewValue(valueType, map[string]tftypes.Value{
+			"type":                   tftypes.NewValue(tftypes.String, "unicode"),
+			"string_value":           tftypes.NewValue(tftypes.String, "configured"),
+			"sensitive_string_value": tftypes.NewValue(tftypes.String, nil),
+			"integer_value":          tftypes.NewValue(tftypes.Number, nil),
+			"boolean_value":          tftypes.NewValue(tftypes.Bool, nil),
+		})
+		values["managed_fields"] = tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, ManagedFieldValue)})
+		return tftypes.NewValue(objectType, values)
+	}
+	req := resource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: makeValue(nil)},
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: makeValue(tftypes.UnknownValue)},
+		State:  tfsdk.State{Schema: schemaResp.Schema, Raw: makeValue(true)},
+	}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+
+	// When
+	diags := planUnmanagedFields(ctx, req, &resp)
+
+	// Then
+	assert.False(t, diags.HasError())
+	var enabled types.Bool
+	resp.Plan.GetAttribute(ctx, path.Root("enabled"), &enabled)
+	assert.True(t, enabled.ValueBool())
+
+	// When
+	req.State = tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}
+	diags = planUnmanagedFields(ctx, req, &resp)
+
+	// Then
+	assert.True(t, diags.HasError())
+}
+
+func TestApplyEnvironmentValuesClaimsBeforeWriting(t *testing.T) {
+	// Given
+	client := newFSClient("master_api_key", "http://localhost")
+	assert.NoError(t, client.ClaimFeatureState("b_env_key", 1, featureStateManagerFeatureState))
+	r := &featureResource{client: client}
+	data := FeatureResourceData{
+		ID:        types.Int64Value(1),
+		ProjectID: types.Int64Value(1),
+		EnvironmentValues: map[string]EnvironmentFeatureStateData{
+			"a_env_key": {Enabled: types.BoolValue(true)},
+			"b_env_key": {Enabled: types.BoolValue(true)},
+		},
+	}
+
+	// When
+	diags := r.applyEnvironmentValues(context.Background(), &data)
+
+	// Then
+	assert.Equal(t, 1, diags.ErrorsCount())
+	assert.Equal(t, "Feature State Managed Twice", diags.Errors()[0].Summary())
+}
@@ -5,12 +5,13 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -30,6 +31,12 @@ type fsProvider struct {
 type providerData struct {
 	MasterAPIKey types.String `tfsdk:"master_api_key"`
 	BaseAPIURL   types.String `tfsdk:"base_api_url"`
+	RefreshCache types.Bool   `tfsdk:"refresh_cache"`
+
+	ChangeRequestMode types.String `tfsdk:"change_request_mode"`
+
+	SensitiveFeatureTag             types.String `tfsdk:"sensitive_feature_tag"`
+	SensitiveFeatureEnvironmentKeys types.Set    `tfsdk:"sensitive_feature_environment_keys"`
 }
 
 func (p *fsProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -67,7 +74,28 @@ func (p *fsProvider) Configure(ctx context.Context, req provider.ConfigureReques
 
 	}
 
-	client := flagsmithapi.NewClient(masterAPIKey, baseAPIURL)
+	refreshCache := data.RefreshCache.IsNull() || data.RefreshCache.ValueBool()
+
+	client := newFSClientWithCache(masterAPIKey, baseAPIURL, refreshCache)
+	if data.ChangeRequestMode.ValueString() != "" {
+		client.changeRequestMode = data.ChangeRequestMode.ValueString()
+	}
+	if data.SensitiveFeatureTag.IsNull() {
+		client.sensitiveFeatureTag = os.Getenv("FLAGSMITH_SENSITIVE_FEATURE_TAG")
+	} else {
+		client.sensitiveFeatureTag = data.SensitiveFeatureTag.ValueString()
+	}
+	if !data.SensitiveFeatureEnvironmentKeys.IsNull() {
+		var environmentKeys []string
+		resp.Diagnostics.Append(data.SensitiveFeatureEnvironmentKeys.ElementsAs(ctx, &environmentKeys, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		client.sensitiveEnvironmentKeys = map[string]bool{}
+		for _, environmentKey := range environmentKeys {
+			client.sensitiveEnvironmentKeys[environmentKey] = true
+		}
+	}
 
 	resp.DataSourceData = client
 	resp.ResourceData = client
@@ -77,9 +105,19 @@ func (p *fsProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		newFeatureResource,
 		newFeatureStateResource,
+		newSegmentOverrideResource,
+		newSegmentOverrideOrderResource,
+		newIdentityOverrideResource,
+		newIdentityResource,
+		newEnvironmentFeatureStatesResource,
+		newEnvironmentPromotionResource,
 		newSegmentResource,
 		newMultivariateResource,
 		newTagResource,
+		newFeatureTagResource,
+		newFeatureOwnerResource,
+		newMetadataFieldResource,
+		newMetadataModelFieldResource,
 		newProjectResource,
 		newEnvironmentResource,
 	}
@@ -90,6 +128,9 @@ func (p *fsProvider) DataSources(ctx context.Context) []func() datasource.DataSo
 	return []func() datasource.DataSource{
 		newOrganisationDataResource,
 		newUserDataResource,
+		newIdentityDataResource,
+		newEnvironmentDiffDataResource,
+		newFeatureStateDataResource,
 	}
 }
 
@@ -107,6 +148,26 @@ func (p *fsProvider) Schema(ctx context.Context, req provider.SchemaRequest, res
 				MarkdownDescription: "Used by api client to connect to flagsmith instance. NOTE: update this if you are running a self hosted version. e.g: https://your.flagsmith.com/api/v1",
 				Optional:            true,
 			},
+			"refresh_cache": schema.BoolAttribute{
+				MarkdownDescription: "Read all the feature states of an environment with a single request the first time one of them is refreshed, and serve the other ones from that snapshot until a feature state of the environment is updated. Set it to false to read every feature state individually. Defaults to true",
+				Optional:            true,
+			},
+			"change_request_mode": schema.StringAttribute{
+				MarkdownDescription: "Default `change_request_mode` of the resources writing feature states. `never` writes them directly, `always` opens a change request instead and `auto` opens one only if the environment requires approvals(i.e: `minimum_change_request_approvals` > 0). Defaults to `never`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(changeRequestModes...),
+				},
+			},
+			"sensitive_feature_tag": schema.StringAttribute{
+				MarkdownDescription: "Label of the tag marking the features whose string values are sensitive. The values of these features are read into `sensitive_string_value` and must be set with it. Can also be set using the environment variable `FLAGSMITH_SENSITIVE_FEATURE_TAG`",
+				Optional:            true,
+			},
+			"sensitive_feature_environment_keys": schema.SetAttribute{
+				MarkdownDescription: "Client side keys of the environments in which `sensitive_feature_tag` applies. The values of the multivariate options are shared by every environment and follow the tag regardless. Defaults to every environment",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
 		},
 	}
 }
@@ -3,7 +3,6 @@ package flagsmith
 import (
 	"context"
 	"fmt"
-	"github.com/Flagsmith/flagsmith-go-api-client"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -14,19 +13,21 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces
 var _ resource.Resource = &environmentResource{}
 var _ resource.ResourceWithImportState = &environmentResource{}
+var _ resource.ResourceWithModifyPlan = &environmentResource{}
 
 func newEnvironmentResource() resource.Resource {
 	return &environmentResource{}
 }
 
 type environmentResource struct {
-	client *flagsmithapi.Client
+	client *fsClient
 }
 
 func (r *environmentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -39,11 +40,11 @@ func (r *environmentResource) Configure(ctx context.Context, req resource.Config
 		return
 	}
 
-	client, ok := req.ProviderData.(*flagsmithapi.Client)
+	client, ok := req.ProviderData.(*fsClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *flagsmithapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -127,10 +128,81 @@ func (t *environmentResource) Schema(ctx context.Context, req resource.SchemaReq
 				Default:             booldefault.StaticBool(true),
 				PlanModifiers:       []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
 			},
+			"metadata": metadataSchema(MetadataModelEnvironment),
+			"use_v2_feature_versioning": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Enable v2 feature versioning, after which feature states are changed by publishing feature versions. NOTE: v2 feature versioning can not be disabled once enabled",
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers:       []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
+			},
 		},
 	}
 }
 
+// ModifyPlan refuses disabling v2 feature versioning, and validates the
+// metadata of new and updated environments against the metadata fields of
+// their organisation
+func (r *environmentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	if !req.State.Raw.IsNull() {
+		var planned, current types.Bool
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("use_v2_feature_versioning"), &planned)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("use_v2_feature_versioning"), &current)...)
+		if current.ValueBool() && planned.Equal(types.BoolValue(false)) {
+			resp.Diagnostics.AddAttributeError(path.Root("use_v2_feature_versioning"), "Invalid Update", "v2 feature versioning can not be disabled once enabled")
+		}
+	}
+	if resp.Diagnostics.HasError() || r.client == nil {
+		return
+	}
+	var projectID types.Int64
+	var metadata types.Map
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("project_id"), &projectID)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("metadata"), &metadata)...)
+	if resp.Diagnostics.HasError() || projectID.IsUnknown() || metadata.IsNull() {
+		return
+	}
+	project, err := r.client.GetProjectByID(projectID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read project, got error: %s", err))
+		return
+	}
+	resp.Diagnostics.Append(validateMetadata(ctx, r.client, project.Organisation, project.ID, MetadataModelEnvironment, metadata)...)
+}
+
+// clientMetadata returns the metadata of the environment as sent to
+// Flagsmith, nil if not managed
+func (r *environmentResource) clientMetadata(data *EnvironmentResourceData) ([]Metadata, error) {
+	if data.Metadata == nil {
+		return nil, nil
+	}
+	project, err := r.client.GetProjectByID(data.ProjectID.ValueInt64())
+	if err != nil {
+		return nil, err
+	}
+	return makeClientMetadata(r.client, project.Organisation, MetadataModelEnvironment, data.Metadata)
+}
+
+// readMetadata sets the metadata of the environment, if managed
+func (r *environmentResource) readMetadata(data *EnvironmentResourceData) error {
+	if data.Metadata == nil {
+		return nil
+	}
+	project, err := r.client.GetProjectByID(data.ProjectID.ValueInt64())
+	if err != nil {
+		return err
+	}
+	clientMetadata, err := r.client.GetEnvironmentMetadata(data.APIKey.ValueString())
+	if err != nil {
+		return err
+	}
+	data.Metadata, err = makeMetadataData(r.client, project.Organisation, MetadataModelEnvironment, clientMetadata)
+	return err
+}
+
 func (r *environmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data EnvironmentResourceData
 
@@ -141,16 +213,31 @@ func (r *environmentResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	clientMetadata, err := r.clientMetadata(&data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve environment metadata, got error: %s", err))
+		return
+	}
 	clientEnvironment := data.ToClientEnvironment()
 
 	// Create the environment
-	err := r.client.CreateEnvironment(clientEnvironment)
+	err = r.client.CreateEnvironmentWithMetadata(clientEnvironment, clientMetadata)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create environment, got error: %s", err))
 		return
 	}
 	resourceData := MakeEnvironmentResourceDataFromClientEnvironment(clientEnvironment)
+	resourceData.Metadata = data.Metadata
+
+	if data.UseV2FeatureVersioning.ValueBool() {
+		err = r.client.EnableV2Versioning(clientEnvironment.APIKey)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to enable v2 feature versioning, got error: %s", err))
+			return
+		}
+		resourceData.UseV2FeatureVersioning = types.BoolValue(true)
+	}
 
 	diags = resp.State.Set(ctx, &resourceData)
 	resp.Diagnostics.Append(diags...)
@@ -173,6 +260,18 @@ func (r *environmentResource) Read(ctx context.Context, req resource.ReadRequest
 	}
 	resourceData := MakeEnvironmentResourceDataFromClientEnvironment(environment)
 
+	versioned, err := r.client.IsV2Versioned(environment.APIKey)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment versioning, got error: %s", err))
+		return
+	}
+	resourceData.UseV2FeatureVersioning = types.BoolValue(versioned)
+	resourceData.Metadata = data.Metadata
+	if err := r.readMetadata(&resourceData); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment metadata, got error: %s", err))
+		return
+	}
+
 	diags = resp.State.Set(ctx, &resourceData)
 	resp.Diagnostics.Append(diags...)
 
@@ -189,16 +288,39 @@ func (r *environmentResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	var state EnvironmentResourceData
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Update: Error reading state data")
+		return
+	}
+	clientMetadata, err := r.clientMetadata(&plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve environment metadata, got error: %s", err))
+		return
+	}
 	// Generate API request body from plan
 	clientEnvironment := plan.ToClientEnvironment()
 
-	err := r.client.UpdateEnvironment(clientEnvironment)
+	err = r.client.UpdateEnvironmentWithMetadata(clientEnvironment, clientMetadata)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update environment, got error: %s", err))
 		return
 	}
 
+	if plan.UseV2FeatureVersioning.ValueBool() && !state.UseV2FeatureVersioning.ValueBool() {
+		err = r.client.EnableV2Versioning(clientEnvironment.APIKey)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to enable v2 feature versioning, got error: %s", err))
+			return
+		}
+	}
+
 	resourceData := MakeEnvironmentResourceDataFromClientEnvironment(clientEnvironment)
+	resourceData.UseV2FeatureVersioning = plan.UseV2FeatureVersioning
+	resourceData.Metadata = plan.Metadata
 
 	// Update the state with the new values
 	diags = resp.State.Set(ctx, &resourceData)
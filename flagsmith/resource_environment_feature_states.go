@@ -0,0 +1,414 @@
+package flagsmith
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &environmentFeatureStatesResource{}
+var _ resource.ResourceWithImportState = &environmentFeatureStatesResource{}
+var _ resource.ResourceWithValidateConfig = &environmentFeatureStatesResource{}
+
+func newEnvironmentFeatureStatesResource() resource.Resource {
+	return &environmentFeatureStatesResource{}
+}
+
+type environmentFeatureStatesResource struct {
+	client *fsClient
+}
+
+func (r *environmentFeatureStatesResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_environment_feature_states"
+}
+
+func (r *environmentFeatureStatesResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*fsClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (t *environmentFeatureStatesResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Feature states/ Remote config values of many features in an environment, managed as a whole. " +
+			"All the feature states are read with a single listing and only the ones that changed are updated.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the resource, same as `environment_key`",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"environment_key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Client side environment key associated with the environment",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"environment_id": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the environment",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+			},
+			"exclusive": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "If true, the features of the project that are not part of `feature_states` are reset to their default(`default_enabled` and `initial_value`) in the environment. Otherwise they are left alone",
+			},
+			"feature_states": schema.MapNestedAttribute{
+				Required:            true,
+				MarkdownDescription: "Feature states of the environment, keyed by feature name or feature ID",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"enabled": schema.BoolAttribute{
+							MarkdownDescription: "Used for enabling/disabling the feature",
+							Required:            true,
+						},
+						"feature_state_value": featureStateValueSchema(),
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *environmentFeatureStatesResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var featureStates types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("feature_states"), &featureStates)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateFeatureStateValues(ctx, featureStates, path.Root("feature_states"))...)
+}
+
+// validateFeatureStateValues errors for the values of a map of
+// EnvironmentFeatureStateData that do not set exactly one of their fields
+func validateFeatureStateValues(ctx context.Context, featureStates types.Map, attribute path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if featureStates.IsNull() || featureStates.IsUnknown() {
+		return diags
+	}
+	var data map[string]EnvironmentFeatureStateData
+	diags.Append(featureStates.ElementsAs(ctx, &data, false)...)
+	if diags.HasError() {
+		return diags
+	}
+	for key, featureState := range data {
+		if featureState.FeatureStateValue == nil {
+			continue
+		}
+		value := featureState.FeatureStateValue
+		set := 0
+		for _, isNull := range []bool{value.StringValue.IsNull(), value.SensitiveStringValue.IsNull(), value.IntegerValue.IsNull(), value.BooleanValue.IsNull()} {
+			if !isNull {
+				set++
+			}
+		}
+		if set != 1 {
+			diags.AddAttributeError(
+				attribute.AtMapKey(key).AtName("feature_state_value"),
+				"Invalid Attribute Combination",
+				"Exactly one of string_value, sensitive_string_value, integer_value or boolean_value must be set",
+			)
+		}
+	}
+	return diags
+}
+
+// environmentFeatures holds the features of a project along with their state
+// in an environment, as returned by a single listing of each
+type environmentFeatures struct {
+	environment   *flagsmithapi.Environment
+	features      []flagsmithapi.Feature
+	featureStates map[int64]*flagsmithapi.FeatureState
+}
+
+func (r *environmentFeatureStatesResource) getEnvironmentFeatures(environmentKey string) (*environmentFeatures, error) {
+	environment, err := r.client.GetEnvironment(environmentKey)
+	if err != nil {
+		return nil, err
+	}
+	features, err := r.client.GetProjectFeatures(environment.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	featureStates, err := r.client.GetEnvironmentFeatureStates(environmentKey)
+	if err != nil {
+		return nil, err
+	}
+	e := environmentFeatures{
+		environment:   environment,
+		features:      features,
+		featureStates: map[int64]*flagsmithapi.FeatureState{},
+	}
+	for i := range featureStates {
+		e.featureStates[featureStates[i].Feature] = &featureStates[i]
+	}
+	return &e, nil
+}
+
+// feature returns the feature identified by the given key, which can either be
+// the name or the ID of the feature
+func (e *environmentFeatures) feature(key string) *flagsmithapi.Feature {
+	for i := range e.features {
+		if e.features[i].Name == key {
+			return &e.features[i]
+		}
+	}
+	if id, err := strconv.ParseInt(key, 10, 64); err == nil {
+		for i := range e.features {
+			if e.features[i].ID != nil && *e.features[i].ID == id {
+				return &e.features[i]
+			}
+		}
+	}
+	return nil
+}
+
+// unmanaged returns the feature states that are not part of the given map and
+// differ from the default of their feature, keyed by feature name
+func (e *environmentFeatures) unmanaged(managed map[int64]bool) map[string]EnvironmentFeatureStateData {
+	featureStates := map[string]EnvironmentFeatureStateData{}
+	for i := range e.features {
+		feature := &e.features[i]
+		featureState, ok := e.featureStates[*feature.ID]
+		if managed[*feature.ID] || !ok {
+			continue
+		}
+		current := MakeEnvironmentFeatureStateDataFromClientFS(featureState)
+		if !current.Equal(MakeEnvironmentFeatureStateDataFromClientFeature(feature)) {
+			featureStates[feature.Name] = current
+		}
+	}
+	return featureStates
+}
+
+// apply updates the feature states of the environment that differ from the
+// plan, and resets the unmanaged ones if the plan is exclusive
+func (r *environmentFeatureStatesResource) apply(ctx context.Context, plan *EnvironmentFeatureStatesResourceData) error {
+	e, err := r.getEnvironmentFeatures(plan.EnvironmentKey.ValueString())
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(plan.FeatureStates))
+	for key := range plan.FeatureStates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	updates := map[int64]EnvironmentFeatureStateData{}
+	managed := map[int64]bool{}
+	for _, key := range keys {
+		feature := e.feature(key)
+		if feature == nil {
+			return fmt.Errorf("feature %q not found in the project of environment %q", key, plan.EnvironmentKey.ValueString())
+		}
+		if managed[*feature.ID] {
+			return fmt.Errorf("feature %q is listed more than once in feature_states", key)
+		}
+		managed[*feature.ID] = true
+		updates[*feature.ID] = plan.FeatureStates[key]
+
+		if value := plan.FeatureStates[key].FeatureStateValue; value != nil && !value.StringValue.IsNull() {
+			sensitive, err := r.client.IsSensitiveFeature(e.environment.APIKey, e.environment.ProjectID, *feature.ID)
+			if err != nil {
+				return err
+			}
+			if sensitive {
+				return fmt.Errorf("feature %q is tagged %q, set its value with sensitive_string_value instead", key, r.client.sensitiveFeatureTag)
+			}
+		}
+	}
+	if plan.Exclusive.ValueBool() {
+		for i := range e.features {
+			if !managed[*e.features[i].ID] {
+				updates[*e.features[i].ID] = MakeEnvironmentFeatureStateDataFromClientFeature(&e.features[i])
+			}
+		}
+	}
+
+	versioned, err := r.client.IsV2Versioned(plan.EnvironmentKey.ValueString())
+	if err != nil {
+		return err
+	}
+
+	updated := 0
+	for featureID, desired := range updates {
+		featureState, ok := e.featureStates[featureID]
+		if !ok && !managed[featureID] {
+			continue
+		}
+		if !ok {
+			return fmt.Errorf("feature state of feature %d not found in environment %q", featureID, plan.EnvironmentKey.ValueString())
+		}
+		if desired.Equal(MakeEnvironmentFeatureStateDataFromClientFS(featureState)) {
+			continue
+		}
+		featureState.Enabled = desired.Enabled.ValueBool()
+		featureState.FeatureStateValue = desired.FeatureStateValue.ToClientFSV()
+		if versioned {
+			err = r.client.PublishFeatureVersion(e.environment.ID, featureID, &FeatureVersion{
+				FeatureStatesToUpdate: []FeatureVersionFeatureState{NewFeatureVersionFeatureState(featureState)},
+			})
+		} else {
+			err = r.client.UpdateFeatureState(featureState, false)
+		}
+		if err != nil {
+			return err
+		}
+		updated++
+	}
+	tflog.Debug(ctx, "Updated environment feature states", map[string]interface{}{"updated": updated, "total": len(updates)})
+
+	plan.ID = plan.EnvironmentKey
+	plan.Environment = types.Int64Value(e.environment.ID)
+	return nil
+}
+
+func (r *environmentFeatureStatesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data EnvironmentFeatureStatesResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, &data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update environment feature states, got error: %s", err))
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *environmentFeatureStatesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data EnvironmentFeatureStatesResourceData
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	// Early return if the state is wrong
+	if diags.HasError() {
+		return
+	}
+
+	e, err := r.getEnvironmentFeatures(data.EnvironmentKey.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment feature states, got error: %s", err))
+		return
+	}
+
+	// The feature states are null right after an import, in which case every
+	// feature state that differs from its default is brought into the state
+	imported := data.FeatureStates == nil
+	if data.Exclusive.IsNull() || data.Exclusive.IsUnknown() {
+		data.Exclusive = types.BoolValue(false)
+	}
+
+	featureStates := map[string]EnvironmentFeatureStateData{}
+	managed := map[int64]bool{}
+	for key := range data.FeatureStates {
+		feature := e.feature(key)
+		if feature == nil {
+			// The feature has been deleted
+			continue
+		}
+		featureState, ok := e.featureStates[*feature.ID]
+		if !ok {
+			continue
+		}
+		managed[*feature.ID] = true
+		featureStates[key] = MakeEnvironmentFeatureStateDataFromClientFS(featureState)
+	}
+	if imported || data.Exclusive.ValueBool() {
+		for key, featureState := range e.unmanaged(managed) {
+			featureStates[key] = featureState
+		}
+	}
+
+	// A value set with sensitive_string_value stays sensitive, and so do the
+	// values of the features tagged as such
+	for key, featureState := range featureStates {
+		sensitive := data.FeatureStates[key].FeatureStateValue.IsSensitive()
+		if feature := e.feature(key); !sensitive && feature != nil {
+			sensitive, err = r.client.IsSensitiveFeature(e.environment.APIKey, e.environment.ProjectID, *feature.ID)
+			if err != nil {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read the tags of the features, got error: %s", err))
+				return
+			}
+		}
+		if sensitive {
+			featureState.FeatureStateValue.MarkSensitive()
+		}
+	}
+
+	data.ID = data.EnvironmentKey
+	data.Environment = types.Int64Value(e.environment.ID)
+	data.FeatureStates = featureStates
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *environmentFeatureStatesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Get plan values
+	var plan EnvironmentFeatureStatesResourceData
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Update: Error reading plan data")
+		return
+	}
+
+	err := r.apply(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update environment feature states, got error: %s", err))
+		return
+	}
+
+	// Update the state with the new values
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *environmentFeatureStatesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The feature states of an environment can not be deleted, they are left
+	// as they are
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *environmentFeatureStatesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("environment_key"), req, resp)
+}
@@ -0,0 +1,85 @@
+package flagsmith_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccEnvironmentFeatureStatesResource(t *testing.T) {
+	featureName := acctest.RandString(10)
+	resourceName := "flagsmith_environment_feature_states.test_feature_states"
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccEnvironmentFeatureStatesResourceConfig(featureName, true, "one", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", environmentKey()),
+					resource.TestCheckResourceAttr(resourceName, "environment_id", strconv.Itoa(environmentID())),
+					resource.TestCheckResourceAttr(resourceName, "exclusive", "false"),
+					resource.TestCheckResourceAttr(resourceName, "feature_states.%", "2"),
+					resource.TestCheckResourceAttr(resourceName, fmt.Sprintf("feature_states.%s_string.enabled", featureName), "true"),
+					resource.TestCheckResourceAttr(resourceName, fmt.Sprintf("feature_states.%s_string.feature_state_value.string_value", featureName), "one"),
+					resource.TestCheckResourceAttr(resourceName, fmt.Sprintf("feature_states.%s_int.feature_state_value.integer_value", featureName), "1"),
+				),
+			},
+
+			// Update testing
+			{
+				Config: testAccEnvironmentFeatureStatesResourceConfig(featureName, false, "two", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "feature_states.%", "2"),
+					resource.TestCheckResourceAttr(resourceName, fmt.Sprintf("feature_states.%s_string.enabled", featureName), "false"),
+					resource.TestCheckResourceAttr(resourceName, fmt.Sprintf("feature_states.%s_string.feature_state_value.string_value", featureName), "two"),
+					resource.TestCheckResourceAttr(resourceName, fmt.Sprintf("feature_states.%s_int.feature_state_value.integer_value", featureName), "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccEnvironmentFeatureStatesResourceConfig(featureName string, isEnabled bool, stringValue string, intValue int) string {
+	return fmt.Sprintf(`
+provider "flagsmith" {
+}
+
+resource "flagsmith_feature" "string_feature" {
+  feature_name = "%[1]s_string"
+  project_uuid = "%[2]s"
+  type         = "STANDARD"
+}
+
+resource "flagsmith_feature" "int_feature" {
+  feature_name = "%[1]s_int"
+  project_uuid = "%[2]s"
+  type         = "STANDARD"
+}
+
+resource "flagsmith_environment_feature_states" "test_feature_states" {
+  environment_key = "%[3]s"
+  feature_states = {
+    (flagsmith_feature.string_feature.feature_name) = {
+      enabled = %[4]t
+      feature_state_value = {
+        type         = "unicode"
+        string_value = "%[5]s"
+      }
+    }
+    (flagsmith_feature.int_feature.feature_name) = {
+      enabled = true
+      feature_state_value = {
+        type          = "int"
+        integer_value = %[6]d
+      }
+    }
+  }
+}
+
+`, featureName, projectUUID(), environmentKey(), isEnabled, stringValue, intValue)
+}
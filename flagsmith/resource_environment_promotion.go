@@ -0,0 +1,651 @@
+package flagsmith
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &environmentPromotionResource{}
+var _ resource.ResourceWithModifyPlan = &environmentPromotionResource{}
+
+const (
+	promotionActionCreate = "create"
+	promotionActionUpdate = "update"
+	promotionActionDelete = "delete"
+)
+
+// environmentPromotionChangeAttrTypes are the attribute types of a change of
+// `changes`
+var environmentPromotionChangeAttrTypes = map[string]attr.Type{
+	"feature_id":           types.Int64Type,
+	"feature_name":         types.StringType,
+	"segment_id":           types.Int64Type,
+	"action":               types.StringType,
+	"enabled":              types.BoolType,
+	"feature_state_value":  types.StringType,
+	"priority":             types.Int64Type,
+	"multivariate_weights": types.MapType{ElemType: types.Float64Type},
+}
+
+func newEnvironmentPromotionResource() resource.Resource {
+	return &environmentPromotionResource{}
+}
+
+type environmentPromotionResource struct {
+	client *fsClient
+}
+
+func (r *environmentPromotionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_environment_promotion"
+}
+
+func (r *environmentPromotionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*fsClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (t *environmentPromotionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Promotion of the feature states of an environment to another environment of the same project. " +
+			"The changes needed to make the target environment match the source environment are computed at plan time, shown as `changes` and applied on apply. " +
+			"Any later difference between the two environments is detected and promoted again. Destroying the resource leaves the target environment as it is.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the resource, `source_environment_key/target_environment_key`",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"source_environment_key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Client side environment key of the environment the feature states are copied from",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"target_environment_key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Client side environment key of the environment the feature states are copied to",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"feature_ids": schema.SetAttribute{
+				Optional:            true,
+				ElementType:         types.Int64Type,
+				MarkdownDescription: "IDs of the features to promote. If neither `feature_ids` nor `tags` is set, every feature of the project is promoted",
+			},
+			"tags": schema.SetAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Labels of the tags of the features to promote, on top of the ones listed in `feature_ids`",
+			},
+			"include_segment_overrides": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "If true, the segment overrides of the promoted features are promoted as well, including their priorities. The segment overrides of the target environment that are not part of the source environment are deleted",
+			},
+			"include_multivariate_weights": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "If true, the percentage allocations of the multivariate options of the promoted feature states are promoted as well",
+			},
+			"triggers": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Arbitrary values that, when changed, delay the computation of `changes` to apply time. Reference the attributes of the resources managing the source environment in the same configuration, so that their changes are promoted by the same apply",
+			},
+			"in_sync": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the target environment matched the source environment when last read",
+			},
+			"changes": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Changes made to the target environment by the promotion, computed at plan time",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"feature_id": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "ID of the feature",
+						},
+						"feature_name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Name of the feature",
+						},
+						"segment_id": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "ID of the segment of the segment override, null for the environment default",
+						},
+						"action": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Action taken on the feature state of the target environment, can be `create`, `update` or `delete`. Only segment overrides are created or deleted",
+						},
+						"enabled": schema.BoolAttribute{
+							Computed:            true,
+							MarkdownDescription: "Promoted enabled state, null if the segment override is deleted",
+						},
+						"feature_state_value": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Promoted value, null if the segment override is deleted. The values of the features tagged with the `sensitive_feature_tag` of the provider are not shown",
+						},
+						"priority": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "Promoted priority of the segment override, null for the environment default",
+						},
+						"multivariate_weights": schema.MapAttribute{
+							Computed:            true,
+							ElementType:         types.Float64Type,
+							MarkdownDescription: "Promoted percentage allocations keyed by multivariate option ID, null unless `include_multivariate_weights` is set",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// promotionChange is a change of a feature state of the target environment,
+// source being nil if the segment override is deleted and target being nil if
+// it is created
+type promotionChange struct {
+	feature *flagsmithapi.Feature
+	action  string
+	source  *comparedFeatureState
+	target  *comparedFeatureState
+}
+
+// promotion holds the changes that make the target environment match the
+// source environment
+type promotion struct {
+	source  *flagsmithapi.Environment
+	target  *flagsmithapi.Environment
+	changes []promotionChange
+
+	// segmentOrder holds the segments of the source segment overrides of
+	// each feature, by priority
+	segmentOrder map[int64][]int64
+}
+
+// promotedFeatures returns the features of the project matching the filters
+// of the promotion, sorted by name
+func (r *environmentPromotionResource) promotedFeatures(ctx context.Context, data *EnvironmentPromotionResourceData, projectID int64) ([]flagsmithapi.Feature, error) {
+	features, err := r.client.GetProjectFeatures(projectID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i].Name < features[j].Name })
+
+	var featureIDs []int64
+	var tags []string
+	if !data.FeatureIDs.IsNull() {
+		if diags := data.FeatureIDs.ElementsAs(ctx, &featureIDs, false); diags.HasError() {
+			return nil, fmt.Errorf("unable to read feature_ids")
+		}
+	}
+	if !data.Tags.IsNull() {
+		if diags := data.Tags.ElementsAs(ctx, &tags, false); diags.HasError() {
+			return nil, fmt.Errorf("unable to read tags")
+		}
+	}
+	if data.FeatureIDs.IsNull() && data.Tags.IsNull() {
+		return features, nil
+	}
+
+	promoted := map[int64]bool{}
+	for _, featureID := range featureIDs {
+		promoted[featureID] = true
+	}
+	if len(tags) > 0 {
+		projectTags, err := r.client.GetProjectTags(projectID)
+		if err != nil {
+			return nil, err
+		}
+		tagIDs := map[int64]bool{}
+		for _, label := range tags {
+			found := false
+			for _, tag := range projectTags {
+				if tag.Name == label && tag.ID != nil {
+					tagIDs[*tag.ID] = true
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("tag %q not found in project %d", label, projectID)
+			}
+		}
+		for _, feature := range features {
+			for _, tagID := range feature.Tags {
+				if tagIDs[tagID] {
+					promoted[*feature.ID] = true
+				}
+			}
+		}
+	}
+
+	filtered := []flagsmithapi.Feature{}
+	for _, feature := range features {
+		if promoted[*feature.ID] {
+			filtered = append(filtered, feature)
+		}
+	}
+	return filtered, nil
+}
+
+// compute returns the changes that make the target environment of the
+// promotion match its source environment
+func (r *environmentPromotionResource) compute(ctx context.Context, data *EnvironmentPromotionResourceData) (*promotion, error) {
+	source, target, err := getEnvironmentPair(r.client, data.SourceEnvironmentKey.ValueString(), data.TargetEnvironmentKey.ValueString())
+	if err != nil {
+		return nil, err
+	}
+	features, err := r.promotedFeatures(ctx, data, source.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	compared, err := compareEnvironments(r.client, source, target, features, data.IncludeSegmentOverrides.ValueBool(), data.IncludeMultivariateWeights.ValueBool())
+	if err != nil {
+		return nil, err
+	}
+
+	p := promotion{source: source, target: target, segmentOrder: map[int64][]int64{}}
+	for _, c := range compared {
+		feature := c.feature
+		if c.source != nil && c.target != nil && !c.source.equal(c.target) {
+			p.changes = append(p.changes, promotionChange{feature: feature, action: promotionActionUpdate, source: c.source, target: c.target})
+		}
+
+		targetBySegment := map[int64]*comparedFeatureState{}
+		for _, override := range c.targetOverrides {
+			targetBySegment[*override.featureState.Segment] = override
+		}
+		for _, s := range c.sourceOverrides {
+			segment := *s.featureState.Segment
+			p.segmentOrder[*feature.ID] = append(p.segmentOrder[*feature.ID], segment)
+			t, ok := targetBySegment[segment]
+			delete(targetBySegment, segment)
+			switch {
+			case !ok:
+				p.changes = append(p.changes, promotionChange{feature: feature, action: promotionActionCreate, source: s})
+			case !s.equal(t):
+				p.changes = append(p.changes, promotionChange{feature: feature, action: promotionActionUpdate, source: s, target: t})
+			}
+		}
+		for _, t := range c.targetOverrides {
+			if _, ok := targetBySegment[*t.featureState.Segment]; ok {
+				p.changes = append(p.changes, promotionChange{feature: feature, action: promotionActionDelete, target: t})
+			}
+		}
+	}
+	tflog.Debug(ctx, "Computed environment promotion", map[string]interface{}{"features": len(features), "changes": len(p.changes)})
+	return &p, nil
+}
+
+// changesData returns the changes of the promotion as a value of `changes`
+func (r *environmentPromotionResource) changesData(ctx context.Context, p *promotion) (types.List, error) {
+	changes := make([]EnvironmentPromotionChangeData, 0, len(p.changes))
+	for _, change := range p.changes {
+		state := change.source
+		if state == nil {
+			state = change.target
+		}
+		changeData := EnvironmentPromotionChangeData{
+			FeatureID:         types.Int64Value(*change.feature.ID),
+			FeatureName:       types.StringValue(change.feature.Name),
+			Segment:           types.Int64PointerValue(state.featureState.Segment),
+			Action:            types.StringValue(change.action),
+			Enabled:           types.BoolNull(),
+			FeatureStateValue: types.StringNull(),
+			Priority:          types.Int64Null(),
+		}
+		if change.source != nil {
+			value := MakeEnvironmentFeatureStateDataFromClientFS(change.source.featureState).FeatureStateValue
+			// The promoted value is read from the source and written to the
+			// target, it is hidden if the tag applies in either of them
+			sourceSensitive, err := r.client.IsSensitiveFeature(p.source.APIKey, p.source.ProjectID, *change.feature.ID)
+			if err != nil {
+				return types.ListNull(types.ObjectType{AttrTypes: environmentPromotionChangeAttrTypes}), err
+			}
+			targetSensitive, err := r.client.IsSensitiveFeature(p.target.APIKey, p.target.ProjectID, *change.feature.ID)
+			if err != nil {
+				return types.ListNull(types.ObjectType{AttrTypes: environmentPromotionChangeAttrTypes}), err
+			}
+			if sourceSensitive || targetSensitive {
+				value.MarkSensitive()
+			}
+			changeData.Enabled = types.BoolValue(change.source.featureState.Enabled)
+			changeData.FeatureStateValue = types.StringValue(describeFeatureStateValue(value))
+			changeData.Priority = types.Int64PointerValue(change.source.featureState.SegmentPriority)
+			changeData.MultivariateWeights = weightsData(change.source.weights)
+		}
+		changes = append(changes, changeData)
+	}
+	list, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: environmentPromotionChangeAttrTypes}, changes)
+	if diags.HasError() {
+		return list, fmt.Errorf("unable to convert changes")
+	}
+	return list, nil
+}
+
+// promotedWeights returns the given weights to set on a feature state whose
+// current weights are given, reusing their IDs
+func promotedWeights(weights, current []MultivariateFeatureStateValue) []MultivariateFeatureStateValue {
+	promoted := make([]MultivariateFeatureStateValue, 0, len(weights))
+	for _, weight := range weights {
+		value := MultivariateFeatureStateValue{
+			MultivariateFeatureOption: weight.MultivariateFeatureOption,
+			PercentageAllocation:      weight.PercentageAllocation,
+		}
+		for _, currentWeight := range current {
+			if currentWeight.MultivariateFeatureOption == weight.MultivariateFeatureOption {
+				value.ID = currentWeight.ID
+			}
+		}
+		promoted = append(promoted, value)
+	}
+	return promoted
+}
+
+// setWeights sets the weights of the multivariate options of a feature state
+// of the target environment
+func (r *environmentPromotionResource) setWeights(featureState *flagsmithapi.FeatureState, weights []MultivariateFeatureStateValue) error {
+	current, err := r.client.GetFeatureStateMultivariateValues(featureState.ID)
+	if err != nil {
+		return err
+	}
+	return r.client.UpdateFeatureStateMultivariateValues(featureState, promotedWeights(weights, current))
+}
+
+// apply makes the changes of the promotion to the target environment, through
+// a published feature version per feature if the target environment uses v2
+// feature versioning
+func (r *environmentPromotionResource) apply(ctx context.Context, p *promotion) error {
+	targetKey := p.target.APIKey
+	versioned, err := r.client.IsV2Versioned(targetKey)
+	if err != nil {
+		return err
+	}
+
+	featureIDs := []int64{}
+	changes := map[int64][]promotionChange{}
+	for _, change := range p.changes {
+		featureID := *change.feature.ID
+		if _, ok := changes[featureID]; !ok {
+			featureIDs = append(featureIDs, featureID)
+		}
+		changes[featureID] = append(changes[featureID], change)
+	}
+
+	for _, featureID := range featureIDs {
+		if versioned {
+			version := FeatureVersion{}
+			for _, change := range changes[featureID] {
+				if change.action == promotionActionDelete {
+					version.SegmentIDsToDeleteOverrides = append(version.SegmentIDsToDeleteOverrides, *change.target.featureState.Segment)
+					continue
+				}
+				featureState := NewFeatureVersionFeatureState(change.source.featureState)
+				featureState.MultivariateFeatureStateValues = promotedWeights(change.source.weights, nil)
+				if change.action == promotionActionCreate {
+					version.FeatureStatesToCreate = append(version.FeatureStatesToCreate, featureState)
+				} else {
+					version.FeatureStatesToUpdate = append(version.FeatureStatesToUpdate, featureState)
+				}
+			}
+			err = r.client.PublishFeatureVersion(p.target.ID, featureID, &version)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = r.applyFeature(ctx, p, featureID, changes[featureID])
+		if err != nil {
+			return err
+		}
+	}
+	tflog.Debug(ctx, "Promoted environment", map[string]interface{}{"features": len(featureIDs), "changes": len(p.changes)})
+	return nil
+}
+
+// applyFeature makes the changes of the promotion to the feature states of a
+// feature in a target environment that does not use v2 feature versioning
+func (r *environmentPromotionResource) applyFeature(ctx context.Context, p *promotion, featureID int64, changes []promotionChange) error {
+	targetKey := p.target.APIKey
+
+	// Creating or deleting an override reorders the other overrides of the feature
+	unlock := r.client.LockSegmentOverrides(targetKey, featureID)
+	defer unlock()
+
+	overridesChanged := false
+	for _, change := range changes {
+		var err error
+		overridesChanged = overridesChanged || change.source == nil || change.source.featureState.Segment != nil
+		switch change.action {
+		case promotionActionCreate:
+			featureState := flagsmithapi.FeatureState{
+				Feature:           featureID,
+				EnvironmentKey:    targetKey,
+				Segment:           change.source.featureState.Segment,
+				SegmentPriority:   change.source.featureState.SegmentPriority,
+				Enabled:           change.source.featureState.Enabled,
+				FeatureStateValue: change.source.featureState.FeatureStateValue,
+			}
+			err = r.client.CreateSegmentOverride(&featureState)
+			if err == nil && change.source.weights != nil {
+				err = r.setWeights(&featureState, change.source.weights)
+			}
+		case promotionActionUpdate:
+			featureState := change.target.featureState
+			featureState.EnvironmentKey = targetKey
+			featureState.Enabled = change.source.featureState.Enabled
+			featureState.FeatureStateValue = change.source.featureState.FeatureStateValue
+			err = r.client.UpdateFeatureState(featureState, false)
+			if err == nil && change.source.weights != nil {
+				err = r.setWeights(featureState, change.source.weights)
+			}
+		case promotionActionDelete:
+			err = r.client.DeleteFeatureSegment(*change.target.featureState.FeatureSegment)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !overridesChanged {
+		return nil
+	}
+
+	// Give the overrides of the target environment the priorities they have in the source environment
+	featureSegments, err := r.client.GetFeatureSegments(p.target.ID, featureID)
+	if err != nil {
+		return err
+	}
+	featureSegmentIDs := map[int64]int64{}
+	for _, featureSegment := range featureSegments {
+		if featureSegment.Segment != nil && featureSegment.ID != nil {
+			featureSegmentIDs[*featureSegment.Segment] = *featureSegment.ID
+		}
+	}
+	ordered := make([]int64, 0, len(p.segmentOrder[featureID]))
+	for _, segment := range p.segmentOrder[featureID] {
+		if featureSegmentID, ok := featureSegmentIDs[segment]; ok {
+			ordered = append(ordered, featureSegmentID)
+		}
+	}
+	if len(ordered) == 0 {
+		return nil
+	}
+	tflog.Debug(ctx, "Reordering segment overrides", map[string]interface{}{"feature_id": featureID, "overrides": len(ordered)})
+	return r.client.ReorderFeatureSegments(ordered)
+}
+
+func (r *environmentPromotionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to compute when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan EnvironmentPromotionResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The changes are computed on apply if the environments or the filters
+	// are not known yet
+	if plan.SourceEnvironmentKey.IsUnknown() || plan.TargetEnvironmentKey.IsUnknown() || plan.FeatureIDs.IsUnknown() || plan.Tags.IsUnknown() || plan.Triggers.IsUnknown() {
+		return
+	}
+
+	// or if the triggers are set or changed, the source environment being
+	// about to change
+	if req.State.Raw.IsNull() && !plan.Triggers.IsNull() {
+		return
+	}
+	if !req.State.Raw.IsNull() {
+		var state EnvironmentPromotionResourceData
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !plan.Triggers.Equal(state.Triggers) {
+			plan.Changes = types.ListUnknown(types.ObjectType{AttrTypes: environmentPromotionChangeAttrTypes})
+			plan.InSync = types.BoolValue(true)
+			resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+			return
+		}
+	}
+	p, err := r.compute(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to compute environment promotion, got error: %s", err))
+		return
+	}
+	plan.Changes, err = r.changesData(ctx, p)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to compute environment promotion, got error: %s", err))
+		return
+	}
+	plan.InSync = types.BoolValue(true)
+	plan.ID = types.StringValue(fmt.Sprintf("%s/%s", plan.SourceEnvironmentKey.ValueString(), plan.TargetEnvironmentKey.ValueString()))
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// promote computes the promotion and applies it, keeping the planned changes
+// if they are known
+func (r *environmentPromotionResource) promote(ctx context.Context, data *EnvironmentPromotionResourceData) error {
+	p, err := r.compute(ctx, data)
+	if err != nil {
+		return err
+	}
+	err = r.apply(ctx, p)
+	if err != nil {
+		return err
+	}
+	if data.Changes.IsUnknown() {
+		data.Changes, err = r.changesData(ctx, p)
+		if err != nil {
+			return err
+		}
+	}
+	data.ID = types.StringValue(fmt.Sprintf("%s/%s", data.SourceEnvironmentKey.ValueString(), data.TargetEnvironmentKey.ValueString()))
+	data.InSync = types.BoolValue(true)
+	return nil
+}
+
+func (r *environmentPromotionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data EnvironmentPromotionResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.promote(ctx, &data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to promote environment, got error: %s", err))
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *environmentPromotionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data EnvironmentPromotionResourceData
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	// Early return if the state is wrong
+	if diags.HasError() {
+		return
+	}
+
+	// Any difference between the environments shows up as a drift, which
+	// the next plan promotes again
+	p, err := r.compute(ctx, &data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment promotion, got error: %s", err))
+		return
+	}
+	data.Changes, err = r.changesData(ctx, p)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment promotion, got error: %s", err))
+		return
+	}
+	data.InSync = types.BoolValue(len(p.changes) == 0)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *environmentPromotionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Get plan values
+	var plan EnvironmentPromotionResourceData
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Update: Error reading plan data")
+		return
+	}
+
+	err := r.promote(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to promote environment, got error: %s", err))
+		return
+	}
+
+	// Update the state with the new values
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *environmentPromotionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// A promotion can not be undone, the target environment is left as it is
+	resp.State.RemoveResource(ctx)
+}
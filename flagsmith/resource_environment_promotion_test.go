@@ -0,0 +1,83 @@
+package flagsmith_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccEnvironmentPromotionResource(t *testing.T) {
+	featureName := acctest.RandString(10)
+	environmentName := acctest.RandString(10)
+	resourceName := "flagsmith_environment_promotion.test_promotion"
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccEnvironmentPromotionResourceConfig(featureName, environmentName, "one"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "source_environment_key", environmentKey()),
+					resource.TestCheckResourceAttrPair(resourceName, "target_environment_key", "flagsmith_environment.test_target", "api_key"),
+					resource.TestCheckResourceAttr(resourceName, "in_sync", "true"),
+					resource.TestCheckResourceAttr(resourceName, "changes.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "changes.0.action", "update"),
+					resource.TestCheckResourceAttr(resourceName, "changes.0.feature_name", featureName),
+					resource.TestCheckResourceAttr(resourceName, "changes.0.enabled", "true"),
+					resource.TestCheckResourceAttr(resourceName, "changes.0.feature_state_value", `"one"`),
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+				),
+			},
+
+			// Update testing
+			{
+				Config: testAccEnvironmentPromotionResourceConfig(featureName, environmentName, "two"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "in_sync", "true"),
+					resource.TestCheckResourceAttr(resourceName, "changes.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "changes.0.feature_state_value", `"two"`),
+				),
+			},
+		},
+	})
+}
+
+func testAccEnvironmentPromotionResourceConfig(featureName, environmentName, value string) string {
+	return fmt.Sprintf(`
+provider "flagsmith" {
+}
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name = "%s"
+  project_uuid = "%s"
+  type         = "STANDARD"
+}
+
+resource "flagsmith_environment" "test_target" {
+  name       = "%s"
+  project_id = %d
+}
+
+resource "flagsmith_feature_state" "test_source" {
+  enabled         = true
+  environment_key = "%s"
+  feature_id      = flagsmith_feature.test_feature.id
+  feature_state_value = {
+    type         = "unicode"
+    string_value = "%s"
+  }
+}
+
+resource "flagsmith_environment_promotion" "test_promotion" {
+  source_environment_key = flagsmith_feature_state.test_source.environment_key
+  target_environment_key = flagsmith_environment.test_target.api_key
+  feature_ids            = [flagsmith_feature.test_feature.id]
+  triggers = {
+    value = flagsmith_feature_state.test_source.feature_state_value.string_value
+  }
+}
+`, featureName, projectUUID(), environmentName, projectID(), environmentKey(), value)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"testing"
 	"strconv"
+	"regexp"
 )
 
 func TestAccEnvironmentResource(t *testing.T) {
@@ -121,3 +122,37 @@ resource "flagsmith_environment" "test_environment" {
 
 `,environmentName, projectID, description)
 }
+
+func TestAccEnvironmentResourceV2FeatureVersioning(t *testing.T) {
+	environmentName := acctest.RandString(16)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckEnvironmentResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEnvironmentResourceWithV2FeatureVersioningConfig(environmentName, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_environment.test_environment", "use_v2_feature_versioning", "true"),
+				),
+			},
+			// Disabling v2 feature versioning is refused during the plan
+			{
+				Config:      testAccEnvironmentResourceWithV2FeatureVersioningConfig(environmentName, false),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`v2 feature versioning can not be disabled once enabled`),
+			},
+		},
+	})
+}
+
+func testAccEnvironmentResourceWithV2FeatureVersioningConfig(environmentName string, useV2FeatureVersioning bool) string {
+	return providerConfig() + fmt.Sprintf(`
+resource "flagsmith_environment" "test_environment" {
+  name                      = "%s"
+  project_id                = %d
+  use_v2_feature_versioning = %t
+}
+`, environmentName, projectID(), useV2FeatureVersioning)
+}
@@ -3,35 +3,61 @@ package flagsmith
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
+
 	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces
 var _ resource.Resource = &featureResource{}
 var _ resource.ResourceWithImportState = &featureResource{}
+var _ resource.ResourceWithModifyPlan = &featureResource{}
+var _ resource.ResourceWithConfigValidators = &featureResource{}
+var _ resource.ResourceWithValidateConfig = &featureResource{}
+var _ resource.ResourceWithUpgradeState = &featureResource{}
 
 func newFeatureResource() resource.Resource {
 	return &featureResource{}
 }
 
 type featureResource struct {
-	client *flagsmithapi.Client
+	client *fsClient
 }
 
+// Modes of destroying a feature, deleting it from the project, archiving it
+// or refusing to destroy it
+const (
+	FeatureDeletionModeDelete  = "delete"
+	FeatureDeletionModeArchive = "archive"
+	FeatureDeletionModePrevent = "prevent"
+)
+
+var featureDeletionModes = []string{FeatureDeletionModeDelete, FeatureDeletionModeArchive, FeatureDeletionModePrevent}
+
+// defaultTagColour is the colour of the tags created for `create_missing_tags`
+const defaultTagColour = "#3d4db6"
+
 func (r *featureResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_feature"
 }
@@ -42,11 +68,11 @@ func (r *featureResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*flagsmithapi.Client)
+	client, ok := req.ProviderData.(*fsClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *flagsmithapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -57,6 +83,7 @@ func (t *featureResource) Schema(ctx context.Context, req resource.SchemaRequest
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Flagsmith Feature/ Remote config",
+		Version:             1,
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
@@ -97,9 +124,42 @@ func (t *featureResource) Schema(ctx context.Context, req resource.SchemaRequest
 			"initial_value": schema.StringAttribute{
 				Computed:            true,
 				Optional:            true,
-				MarkdownDescription: "Determines the initial value of the feature.",
+				MarkdownDescription: "Determines the initial value of the feature. NOTE: Conflicts with initial_feature_state_value",
 				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
+			"initial_feature_state_value": schema.SingleNestedAttribute{
+				Computed:            true,
+				Optional:            true,
+				MarkdownDescription: "Typed initial value of the feature. Unlike initial_value, whose type Flagsmith infers, its type is kept in the feature states of the environments existing when the feature is created. If unspecified, it is inferred from initial_value. NOTE: Conflicts with initial_value, and exactly one of string_value, integer_value or boolean_value must be set",
+				PlanModifiers:       []planmodifier.Object{objectplanmodifier.UseStateForUnknown()},
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						MarkdownDescription: "Type of the initial value, can be `unicode`, `int` or `bool`",
+						Required:            true,
+						Validators: []validator.String{
+							stringvalidator.OneOf([]string{"unicode", "int", "bool"}...),
+						},
+					},
+					"string_value": schema.StringAttribute{
+						MarkdownDescription: "String value of the feature if the type is `unicode`",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.ExactlyOneOf(
+								path.MatchRelative().AtParent().AtName("integer_value"),
+								path.MatchRelative().AtParent().AtName("boolean_value"),
+							),
+						},
+					},
+					"integer_value": schema.Int64Attribute{
+						MarkdownDescription: "Integer value of the feature if the type is `int`",
+						Optional:            true,
+					},
+					"boolean_value": schema.BoolAttribute{
+						MarkdownDescription: "Boolean value of the feature if the type is `bool`",
+						Optional:            true,
+					},
+				},
+			},
 			"description": schema.StringAttribute{
 				Optional:            true,
 				MarkdownDescription: "Description of the feature",
@@ -120,11 +180,90 @@ func (t *featureResource) Schema(ctx context.Context, req resource.SchemaRequest
 				ElementType:         types.Int64Type,
 				MarkdownDescription: "List of group IDs representing the group owners of the feature.",
 			},
+			"owner_emails": schema.SetAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Emails of the users of the organisation owning the feature. NOTE: Conflicts with owners",
+			},
+			"group_owner_names": schema.SetAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Names of the groups of the organisation owning the feature. NOTE: Conflicts with group_owners",
+			},
 			"tags": schema.SetAttribute{
 				Optional:            true,
 				ElementType:         types.Int64Type,
 				MarkdownDescription: "List of tag IDs representing the tags attached to the feature.",
 			},
+			"tag_names": schema.SetAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Names of the tags of the project attached to the feature. NOTE: Conflicts with tags",
+			},
+			"create_missing_tags": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Whether to create the tags of tag_names missing from the project, with a default colour. If unspecified, it will default to false",
+				Default:             booldefault.StaticBool(false),
+			},
+			"tags_authoritative": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Whether tags or tag_names hold all the tags of the feature. If false, only the tags they hold are attached and detached, and the ones attached otherwise, such as by flagsmith_feature_tag resources, are left untouched. If unspecified, it will default to true",
+				Default:             booldefault.StaticBool(true),
+			},
+			"owners_authoritative": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Whether owners, group_owners, owner_emails and group_owner_names hold all the owners of the feature. If false, only the owners they hold are added and removed, and the ones added otherwise, such as by flagsmith_feature_owner resources, are left untouched. If unspecified, it will default to true",
+				Default:             booldefault.StaticBool(true),
+			},
+			"environment_values": schema.MapNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "Feature states of the feature in the environments of the project, keyed by environment key. The environments left out are not managed. NOTE: Conflicts with the flagsmith_feature_state resources of the same environments",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"enabled": schema.BoolAttribute{
+							MarkdownDescription: "Used for enabling/disabling the feature",
+							Required:            true,
+						},
+						"feature_state_value": featureStateValueSchema(),
+					},
+				},
+			},
+			"include_environment_states": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Whether to read the feature states of the feature in every environment of the project into environment_states, at the cost of a few API calls per environment. If unspecified, it will default to false",
+				Default:             booldefault.StaticBool(false),
+			},
+			"environment_states": schema.MapNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Feature states of the feature in the environments of the project, keyed by environment name. Null unless include_environment_states is set",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"enabled": schema.BoolAttribute{
+							Computed:            true,
+							MarkdownDescription: "Whether the feature is enabled in the environment",
+						},
+						"feature_state_value": computedFeatureStateValueResourceSchema("Value of the feature in the environment"),
+						"segment_override_count": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "Number of segment overrides of the feature in the environment",
+						},
+					},
+				},
+			},
+			"metadata": metadataSchema(MetadataModelFeature),
+			"deletion_mode": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "What destroying the resource does to the feature: `delete` deletes it along with its feature states in every environment, `archive` archives it and `prevent` fails the destroy. The mode is kept in the state, so it still applies once the resource is removed from the configuration. If unspecified, it will default to `delete`",
+				Default:             stringdefault.StaticString(FeatureDeletionModeDelete),
+				Validators: []validator.String{
+					stringvalidator.OneOf(featureDeletionModes...),
+				},
+			},
 			"project_uuid": schema.StringAttribute{
 				MarkdownDescription: "UUID of project the feature belongs to",
 				Required:            true,
@@ -134,6 +273,677 @@ func (t *featureResource) Schema(ctx context.Context, req resource.SchemaRequest
 	}
 }
 
+func (r *featureResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(
+			path.MatchRoot("owners"),
+			path.MatchRoot("owner_emails"),
+		),
+		resourcevalidator.Conflicting(
+			path.MatchRoot("group_owners"),
+			path.MatchRoot("group_owner_names"),
+		),
+		resourcevalidator.Conflicting(
+			path.MatchRoot("tags"),
+			path.MatchRoot("tag_names"),
+		),
+		resourcevalidator.Conflicting(
+			path.MatchRoot("initial_value"),
+			path.MatchRoot("initial_feature_state_value"),
+		),
+	}
+}
+
+func (r *featureResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var environmentValues types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("environment_values"), &environmentValues)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateFeatureStateValues(ctx, environmentValues, path.Root("environment_values"))...)
+}
+
+// featureRulesData holds the attributes of a feature validated against the
+// rules of its project, read one by one since the others may be unknown
+type featureRulesData struct {
+	Name            types.String
+	ProjectUUID     types.String
+	Owners          types.Set
+	GroupOwners     types.Set
+	OwnerEmails     types.Set
+	GroupOwnerNames types.Set
+	TagNames        types.Set
+	Metadata        types.Map
+
+	CreateMissingTags   types.Bool
+	OwnersAuthoritative types.Bool
+}
+
+func getFeatureRulesData(ctx context.Context, getAttribute func(context.Context, path.Path, interface{}) diag.Diagnostics, diags *diag.Diagnostics) *featureRulesData {
+	var data featureRulesData
+	diags.Append(getAttribute(ctx, path.Root("feature_name"), &data.Name)...)
+	diags.Append(getAttribute(ctx, path.Root("project_uuid"), &data.ProjectUUID)...)
+	diags.Append(getAttribute(ctx, path.Root("owners"), &data.Owners)...)
+	diags.Append(getAttribute(ctx, path.Root("group_owners"), &data.GroupOwners)...)
+	diags.Append(getAttribute(ctx, path.Root("owner_emails"), &data.OwnerEmails)...)
+	diags.Append(getAttribute(ctx, path.Root("group_owner_names"), &data.GroupOwnerNames)...)
+	diags.Append(getAttribute(ctx, path.Root("tag_names"), &data.TagNames)...)
+	diags.Append(getAttribute(ctx, path.Root("create_missing_tags"), &data.CreateMissingTags)...)
+	diags.Append(getAttribute(ctx, path.Root("owners_authoritative"), &data.OwnersAuthoritative)...)
+	diags.Append(getAttribute(ctx, path.Root("metadata"), &data.Metadata)...)
+	return &data
+}
+
+// ownerCount returns the number of owners and group owners of the feature,
+// set by ID or by email and name, falling back to the ones of fallback for
+// the owners left unset since they are then left untouched. The second return
+// value is false if unknown.
+func (d *featureRulesData) ownerCount(fallback *featureRulesData) (int, bool) {
+	count := 0
+	pairs := [][]types.Set{{d.Owners, d.OwnerEmails}, {d.GroupOwners, d.GroupOwnerNames}}
+	for i, pair := range pairs {
+		if pair[0].IsNull() && pair[1].IsNull() && fallback != nil {
+			pair = [][]types.Set{{fallback.Owners, fallback.OwnerEmails}, {fallback.GroupOwners, fallback.GroupOwnerNames}}[i]
+		}
+		for _, owners := range pair {
+			if owners.IsUnknown() {
+				return 0, false
+			}
+			count += len(owners.Elements())
+		}
+	}
+	return count, true
+}
+
+// knownStrings returns the elements of a set of strings, false if any is unknown
+func knownStrings(set types.Set) ([]string, bool) {
+	if set.IsUnknown() {
+		return nil, false
+	}
+	values := []string{}
+	for _, element := range set.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsUnknown() {
+			return nil, false
+		}
+		values = append(values, value.ValueString())
+	}
+	return values, true
+}
+
+// validateRules adds attribute errors for the rules of its project a new or
+// updated feature breaks. state is nil for new features.
+func (r *featureResource) validateRules(ctx context.Context, plan, state *featureRulesData, diags *diag.Diagnostics) {
+	if plan.Name.IsUnknown() {
+		return
+	}
+	if plan.ProjectUUID.IsUnknown() {
+		r.validatePlannedRules(plan, diags)
+		return
+	}
+	if state != nil && !state.ProjectUUID.Equal(plan.ProjectUUID) {
+		state = nil
+	}
+	rules, err := r.client.GetFeatureRules(plan.ProjectUUID.ValueString())
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read project, got error: %s", err))
+		return
+	}
+
+	// Leave the features named before the rules were set alone
+	if state == nil || !state.Name.Equal(plan.Name) {
+		if err := rules.ValidateName(plan.Name.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("feature_name"), "Invalid Feature Name", err.Error())
+		}
+	}
+
+	if emails, ok := knownStrings(plan.OwnerEmails); ok && len(emails) > 0 {
+		if _, err := r.client.GetUserIDs(rules.OrganisationID, emails); err != nil {
+			diags.AddAttributeError(path.Root("owner_emails"), "Unknown Feature Owner", fmt.Sprintf("Unable to resolve the owners of the feature: %s", err))
+		}
+	}
+	if names, ok := knownStrings(plan.GroupOwnerNames); ok && len(names) > 0 {
+		if _, err := r.client.GetGroupIDs(rules.OrganisationID, names); err != nil {
+			diags.AddAttributeError(path.Root("group_owner_names"), "Unknown Feature Group Owner", fmt.Sprintf("Unable to resolve the group owners of the feature: %s", err))
+		}
+	}
+
+	// Tags renamed since are planned as a change of tag_names
+	if names, ok := knownStrings(plan.TagNames); ok && len(names) > 0 && !plan.CreateMissingTags.ValueBool() {
+		if _, err := r.client.GetTagIDs(rules.ProjectID, names); err != nil {
+			diags.AddAttributeWarning(path.Root("tag_names"), "Unknown Feature Tag", fmt.Sprintf("Applying will fail unless the tags are created first or create_missing_tags is set: %s", err))
+		}
+	}
+
+	diags.Append(validateMetadata(ctx, r.client, rules.OrganisationID, rules.ProjectID, MetadataModelFeature, plan.Metadata)...)
+
+	owners, known := plan.ownerCount(state)
+	if !known {
+		return
+	}
+	if state == nil {
+		if err := rules.ValidateOwners(owners); err != nil {
+			diags.AddAttributeError(path.Root("owners"), "Feature Owners Required", err.Error())
+		}
+		return
+	}
+	// Only refuse removing the last owner of an existing feature. The feature
+	// may have other owners when not authoritative over them, which is
+	// checked on update instead.
+	if plan.OwnersAuthoritative.Equal(types.BoolValue(false)) {
+		return
+	}
+	if current, _ := state.ownerCount(nil); current > 0 {
+		if err := rules.ValidateOwners(owners); err != nil {
+			diags.AddAttributeError(path.Root("owners"), "Feature Owners Required", fmt.Sprintf("Removing the last owner of the feature: %s", err))
+		}
+	}
+}
+
+// validatePlannedRules validates the name and the owners of a feature of a
+// project created in the same plan against the planned rules of the project.
+// Its tags and metadata are only validated on create, once the project exists.
+func (r *featureResource) validatePlannedRules(plan *featureRulesData, diags *diag.Diagnostics) {
+	rules, ok := r.client.GetPlannedFeatureRules()
+	if !ok {
+		return
+	}
+	if err := rules.ValidateName(plan.Name.ValueString()); err != nil {
+		diags.AddAttributeError(path.Root("feature_name"), "Invalid Feature Name", err.Error())
+	}
+	if owners, known := plan.ownerCount(nil); known {
+		if err := rules.ValidateOwners(owners); err != nil {
+			diags.AddAttributeError(path.Root("owners"), "Feature Owners Required", err.Error())
+		}
+	}
+}
+
+// planInitialValues plans initial_value and initial_feature_state_value from
+// the one of them set in the configuration, so that both stay in sync
+func planInitialValues(ctx context.Context, config tfsdk.Config, plan *tfsdk.Plan) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var initialValue types.String
+	var typedValue types.Object
+	diags.Append(config.GetAttribute(ctx, path.Root("initial_value"), &initialValue)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("initial_feature_state_value"), &typedValue)...)
+	if diags.HasError() {
+		return diags
+	}
+	switch {
+	case !typedValue.IsNull():
+		var value InitialFeatureStateValue
+		if !typedValue.IsUnknown() {
+			diags.Append(typedValue.As(ctx, &value, basetypes.ObjectAsOptions{})...)
+		}
+		if typedValue.IsUnknown() || value.Type.IsUnknown() || value.StringValue.IsUnknown() || value.IntegerValue.IsUnknown() || value.BooleanValue.IsUnknown() {
+			diags.Append(plan.SetAttribute(ctx, path.Root("initial_value"), types.StringUnknown())...)
+			return diags
+		}
+		diags.Append(plan.SetAttribute(ctx, path.Root("initial_value"), value.String())...)
+	case initialValue.IsUnknown():
+		diags.Append(plan.SetAttribute(ctx, path.Root("initial_feature_state_value"), types.ObjectUnknown(typedValue.AttributeTypes(ctx)))...)
+	case !initialValue.IsNull():
+		diags.Append(plan.SetAttribute(ctx, path.Root("initial_feature_state_value"), MakeInitialFeatureStateValue(initialValue.ValueString()))...)
+	}
+	return diags
+}
+
+// ModifyPlan validates new and updated features against the rules of their
+// project, the planned ones for a project created in the same plan
+func (r *featureResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	resp.Diagnostics.Append(planInitialValues(ctx, req.Config, &resp.Plan)...)
+	if resp.Diagnostics.HasError() || r.client == nil {
+		return
+	}
+	plan := getFeatureRulesData(ctx, req.Plan.GetAttribute, &resp.Diagnostics)
+	var state *featureRulesData
+	if !req.State.Raw.IsNull() {
+		state = getFeatureRulesData(ctx, req.State.GetAttribute, &resp.Diagnostics)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.validateRules(ctx, plan, state, &resp.Diagnostics)
+
+	// The ID of a new feature, and so the feature of the flagsmith_feature_state
+	// referencing it, is unknown until the apply. The feature then claims its
+	// feature states before writing any, and the flagsmith_feature_state
+	// planned again with the known ID fails before writing its own.
+	var featureID types.Int64
+	var environmentValues types.Map
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("id"), &featureID)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("environment_values"), &environmentValues)...)
+	if resp.Diagnostics.HasError() || featureID.IsUnknown() || featureID.IsNull() || environmentValues.IsUnknown() {
+		return
+	}
+	for environmentKey := range environmentValues.Elements() {
+		resp.Diagnostics.Append(r.claimFeatureState(environmentKey, featureID.ValueInt64())...)
+	}
+}
+
+// claimFeatureState errors if the feature state of the feature in the
+// environment is also managed by a flagsmith_feature_state
+func (r *featureResource) claimFeatureState(environmentKey string, featureID int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+	err := r.client.ClaimFeatureState(environmentKey, featureID, featureStateManagerFeature)
+	if err != nil {
+		diags.AddAttributeError(path.Root("environment_values").AtMapKey(environmentKey), "Feature State Managed Twice", err.Error())
+	}
+	return diags
+}
+
+// applyEnvironmentValues updates the feature states of the environments of
+// environment_values that differ from it. Every feature state is claimed
+// before the first one is written, so that none is written if one of them is
+// managed by a flagsmith_feature_state.
+func (r *featureResource) applyEnvironmentValues(ctx context.Context, data *FeatureResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	featureID := data.ID.ValueInt64()
+	environmentKeys := make([]string, 0, len(data.EnvironmentValues))
+	for environmentKey := range data.EnvironmentValues {
+		environmentKeys = append(environmentKeys, environmentKey)
+	}
+	sort.Strings(environmentKeys)
+	for _, environmentKey := range environmentKeys {
+		diags.Append(r.claimFeatureState(environmentKey, featureID)...)
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	for _, environmentKey := range environmentKeys {
+		desired := data.EnvironmentValues[environmentKey]
+		valuePath := path.Root("environment_values").AtMapKey(environmentKey).AtName("feature_state_value")
+		sensitive, err := r.client.IsSensitiveFeature(environmentKey, data.ProjectID.ValueInt64(), featureID)
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to read feature tags, got error: %s", err))
+			return diags
+		}
+		if sensitive && desired.FeatureStateValue != nil && !desired.FeatureStateValue.StringValue.IsNull() {
+			diags.AddAttributeError(valuePath.AtName("string_value"), "Sensitive Feature Value",
+				fmt.Sprintf("Feature %q is tagged %q, set its value with sensitive_string_value instead", data.Name.ValueString(), r.client.sensitiveFeatureTag))
+			return diags
+		}
+
+		environment, err := r.client.GetEnvironment(environmentKey)
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to get environment %q, got error: %s", environmentKey, err))
+			return diags
+		}
+		if environment.ProjectID != data.ProjectID.ValueInt64() {
+			diags.AddAttributeError(path.Root("environment_values").AtMapKey(environmentKey), "Invalid Environment",
+				fmt.Sprintf("Environment %q does not belong to the project of the feature", environmentKey))
+			return diags
+		}
+		featureState, err := r.client.GetCachedEnvironmentFeatureState(ctx, environmentKey, "", featureID)
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to get feature state of environment %q, got error: %s", environmentKey, err))
+			return diags
+		}
+		if desired.Equal(MakeEnvironmentFeatureStateDataFromClientFS(featureState)) {
+			continue
+		}
+		featureState.Enabled = desired.Enabled.ValueBool()
+		featureState.FeatureStateValue = desired.FeatureStateValue.ToClientFSV()
+		if err := r.writeEnvironmentFeatureState(environment, featureState); err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to update feature state of environment %q, got error: %s", environmentKey, err))
+			return diags
+		}
+	}
+	return diags
+}
+
+// applyInitialFeatureStateValue sets the value of the feature states of a new
+// feature to its typed initial value in the environments of its project
+// where Flagsmith inferred another type, leaving out the ones of
+// environment_values
+func (r *featureResource) applyInitialFeatureStateValue(ctx context.Context, data *FeatureResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	environments, err := r.client.GetProjectEnvironments(data.ProjectID.ValueInt64())
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to get environments, got error: %s", err))
+		return diags
+	}
+	value := data.InitialFeatureStateValue.FeatureStateValue()
+	for i := range environments {
+		environment := &environments[i]
+		if _, ok := data.EnvironmentValues[environment.APIKey]; ok {
+			continue
+		}
+		featureState, err := r.client.GetCachedEnvironmentFeatureState(ctx, environment.APIKey, "", data.ID.ValueInt64())
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to get feature state of environment %q, got error: %s", environment.Name, err))
+			return diags
+		}
+		if value.Equal(MakeEnvironmentFeatureStateDataFromClientFS(featureState).FeatureStateValue) {
+			continue
+		}
+		featureState.FeatureStateValue = value.ToClientFSV()
+		if err := r.writeEnvironmentFeatureState(environment, featureState); err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to update feature state of environment %q, got error: %s", environment.Name, err))
+			return diags
+		}
+	}
+	return diags
+}
+
+// writeEnvironmentFeatureState updates the environment feature state of a
+// feature, publishing a new version of the feature in versioned environments
+func (r *featureResource) writeEnvironmentFeatureState(environment *flagsmithapi.Environment, featureState *flagsmithapi.FeatureState) error {
+	featureState.EnvironmentKey = environment.APIKey
+	versioned, err := r.client.IsV2Versioned(environment.APIKey)
+	if err != nil {
+		return err
+	}
+	if versioned {
+		return r.client.PublishFeatureVersion(environment.ID, featureState.Feature, &FeatureVersion{
+			FeatureStatesToUpdate: []FeatureVersionFeatureState{NewFeatureVersionFeatureState(featureState)},
+		})
+	}
+	return r.client.UpdateFeatureState(featureState, false)
+}
+
+// readEnvironmentValues reads back the feature states of the environments of
+// environment_values
+func (r *featureResource) readEnvironmentValues(ctx context.Context, data *FeatureResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if data.EnvironmentValues == nil {
+		return diags
+	}
+	environmentValues := map[string]EnvironmentFeatureStateData{}
+	for environmentKey, current := range data.EnvironmentValues {
+		featureState, err := r.client.GetCachedEnvironmentFeatureState(ctx, environmentKey, "", data.ID.ValueInt64())
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to get feature state of environment %q, got error: %s", environmentKey, err))
+			return diags
+		}
+		environmentValue := MakeEnvironmentFeatureStateDataFromClientFS(featureState)
+		if current.FeatureStateValue.IsSensitive() {
+			environmentValue.FeatureStateValue.MarkSensitive()
+		}
+		environmentValues[environmentKey] = environmentValue
+	}
+	data.EnvironmentValues = environmentValues
+	return diags
+}
+
+// resolveReferences sets the owners, group owners and tags of the feature from
+// its owner emails, group owner names and tag names, creating the missing tags
+// if asked to
+func (r *featureResource) resolveReferences(data *FeatureResourceData) error {
+	if data.OwnerEmails == nil && data.GroupOwnerNames == nil && data.TagNames == nil {
+		return nil
+	}
+	rules, err := r.client.GetFeatureRules(data.ProjectUUID.ValueString())
+	if err != nil {
+		return err
+	}
+	if data.OwnerEmails != nil {
+		emails := []string{}
+		for _, email := range *data.OwnerEmails {
+			emails = append(emails, email.ValueString())
+		}
+		userIDs, err := r.client.GetUserIDs(rules.OrganisationID, emails)
+		if err != nil {
+			return err
+		}
+		owners := []types.Int64{}
+		for _, userID := range userIDs {
+			owners = append(owners, types.Int64Value(userID))
+		}
+		data.Owners = &owners
+	}
+	if data.GroupOwnerNames != nil {
+		names := []string{}
+		for _, name := range *data.GroupOwnerNames {
+			names = append(names, name.ValueString())
+		}
+		groupIDs, err := r.client.GetGroupIDs(rules.OrganisationID, names)
+		if err != nil {
+			return err
+		}
+		groupOwners := []types.Int64{}
+		for _, groupID := range groupIDs {
+			groupOwners = append(groupOwners, types.Int64Value(groupID))
+		}
+		data.GroupOwners = &groupOwners
+	}
+	if data.TagNames != nil {
+		tags := []types.Int64{}
+		for _, name := range *data.TagNames {
+			tagID, err := r.getOrCreateTag(rules.ProjectID, name.ValueString(), data.CreateMissingTags.ValueBool())
+			if err != nil {
+				return err
+			}
+			tags = append(tags, types.Int64Value(tagID))
+		}
+		data.Tags = &tags
+	}
+	return nil
+}
+
+// getOrCreateTag returns the ID of the tag of the project with the given name,
+// creating it if missing and create is set
+func (r *featureResource) getOrCreateTag(projectID int64, name string, create bool) (int64, error) {
+	tagIDs, err := r.client.GetTagIDs(projectID, []string{name})
+	if err == nil {
+		return tagIDs[0], nil
+	}
+	if _, ok := err.(TagNotFoundError); !ok || !create {
+		return 0, err
+	}
+	tag := flagsmithapi.Tag{Name: name, Colour: defaultTagColour, ProjectID: &projectID}
+	if err := r.client.CreateTag(&tag); err != nil {
+		return 0, err
+	}
+	return *tag.ID, nil
+}
+
+// normaliseReferences reports the owners, group owners and tags of the feature
+// by email and name instead of ID if config refers to them that way
+func (r *featureResource) normaliseReferences(data *FeatureResourceData, config *FeatureResourceData) error {
+	if config.OwnerEmails == nil && config.GroupOwnerNames == nil && config.TagNames == nil {
+		return nil
+	}
+	rules, err := r.client.GetFeatureRules(data.ProjectUUID.ValueString())
+	if err != nil {
+		return err
+	}
+	if config.OwnerEmails != nil {
+		userIDs := []int64{}
+		if data.Owners != nil {
+			for _, owner := range *data.Owners {
+				userIDs = append(userIDs, owner.ValueInt64())
+			}
+		}
+		emails, err := r.client.GetUserEmails(rules.OrganisationID, userIDs)
+		if err != nil {
+			return err
+		}
+		ownerEmails := []types.String{}
+		for _, email := range emails {
+			ownerEmails = append(ownerEmails, types.StringValue(email))
+		}
+		data.OwnerEmails = &ownerEmails
+		data.Owners = nil
+	}
+	if config.GroupOwnerNames != nil {
+		groupIDs := []int64{}
+		if data.GroupOwners != nil {
+			for _, groupOwner := range *data.GroupOwners {
+				groupIDs = append(groupIDs, groupOwner.ValueInt64())
+			}
+		}
+		names, err := r.client.GetGroupNames(rules.OrganisationID, groupIDs)
+		if err != nil {
+			return err
+		}
+		groupOwnerNames := []types.String{}
+		for _, name := range names {
+			groupOwnerNames = append(groupOwnerNames, types.StringValue(name))
+		}
+		data.GroupOwnerNames = &groupOwnerNames
+		data.GroupOwners = nil
+	}
+	if config.TagNames != nil {
+		tagIDs := []int64{}
+		if data.Tags != nil {
+			for _, tag := range *data.Tags {
+				tagIDs = append(tagIDs, tag.ValueInt64())
+			}
+		}
+		names, err := r.client.GetTagNames(rules.ProjectID, tagIDs)
+		if err != nil {
+			return err
+		}
+		tagNames := []types.String{}
+		for _, name := range names {
+			tagNames = append(tagNames, types.StringValue(name))
+		}
+		data.TagNames = &tagNames
+		data.Tags = nil
+	}
+	return nil
+}
+
+// keepManaged returns the values that are also in managed, nil if managed is
+func keepManaged[T comparable](values, managed *[]T) *[]T {
+	if managed == nil {
+		return nil
+	}
+	kept := []T{}
+	if values != nil {
+		for _, value := range *values {
+			if slices.Contains(*managed, value) {
+				kept = append(kept, value)
+			}
+		}
+	}
+	return &kept
+}
+
+// keepManagedReferences leaves out of the tags, and of the owners, of the
+// feature the ones not in managed, unless the feature is authoritative over
+// them
+func keepManagedReferences(data *FeatureResourceData, managed *FeatureResourceData) {
+	if !data.TagsAuthoritative.ValueBool() {
+		data.Tags = keepManaged(data.Tags, managed.Tags)
+		data.TagNames = keepManaged(data.TagNames, managed.TagNames)
+	}
+	if !data.OwnersAuthoritative.ValueBool() {
+		data.Owners = keepManaged(data.Owners, managed.Owners)
+		data.GroupOwners = keepManaged(data.GroupOwners, managed.GroupOwners)
+		data.OwnerEmails = keepManaged(data.OwnerEmails, managed.OwnerEmails)
+		data.GroupOwnerNames = keepManaged(data.GroupOwnerNames, managed.GroupOwnerNames)
+	}
+}
+
+// validateRemainingOwners errors if the owners removed from a feature in a
+// project enforcing feature owners leave it with none, counting the owners
+// attached otherwise
+func (r *featureResource) validateRemainingOwners(projectUUID string, plan, state *flagsmithapi.Feature) diag.Diagnostics {
+	var diags diag.Diagnostics
+	rules, err := r.client.GetFeatureRules(projectUUID)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read the feature rules of the project, got error: %s", err))
+		return diags
+	}
+	if !rules.EnforceOwners {
+		return diags
+	}
+	current, err := r.client.GetFeature(plan.UUID)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read feature owners, got error: %s", err))
+		return diags
+	}
+	owners := remainingIDs(current.Owners, plan.Owners, state.Owners)
+	groupOwners := remainingIDs(current.GroupOwners, plan.GroupOwners, state.GroupOwners)
+	if err := rules.ValidateOwners(len(owners) + len(groupOwners)); err != nil {
+		diags.AddAttributeError(path.Root("owners"), "Feature Owners Required", fmt.Sprintf("Removing the last owner of the feature: %s", err))
+	}
+	return diags
+}
+
+// clientMetadata returns the metadata of the feature as sent to Flagsmith, nil
+// if not managed
+func (r *featureResource) clientMetadata(data *FeatureResourceData) ([]Metadata, error) {
+	if data.Metadata == nil {
+		return nil, nil
+	}
+	rules, err := r.client.GetFeatureRules(data.ProjectUUID.ValueString())
+	if err != nil {
+		return nil, err
+	}
+	return makeClientMetadata(r.client, rules.OrganisationID, MetadataModelFeature, data.Metadata)
+}
+
+// readMetadata sets the metadata of the feature, if managed
+func (r *featureResource) readMetadata(data *FeatureResourceData) error {
+	if data.Metadata == nil {
+		return nil
+	}
+	rules, err := r.client.GetFeatureRules(data.ProjectUUID.ValueString())
+	if err != nil {
+		return err
+	}
+	clientMetadata, err := r.client.GetFeatureMetadata(data.ProjectID.ValueInt64(), data.ID.ValueInt64())
+	if err != nil {
+		return err
+	}
+	data.Metadata, err = makeMetadataData(r.client, rules.OrganisationID, MetadataModelFeature, clientMetadata)
+	return err
+}
+
+// readEnvironmentStates sets the feature states of the feature in the
+// environments of its project, if asked to
+func (r *featureResource) readEnvironmentStates(ctx context.Context, data *FeatureResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	data.EnvironmentStates = types.MapNull(featureEnvironmentStateType)
+	if !data.IncludeEnvironmentStates.ValueBool() {
+		return diags
+	}
+	projectID := data.ProjectID.ValueInt64()
+	featureID := data.ID.ValueInt64()
+	environments, err := r.client.GetProjectEnvironments(projectID)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to get environments, got error: %s", err))
+		return diags
+	}
+
+	states := map[string]FeatureEnvironmentStateData{}
+	for _, environment := range environments {
+		sensitive, err := r.client.IsSensitiveFeature(environment.APIKey, projectID, featureID)
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to read feature tags, got error: %s", err))
+			return diags
+		}
+		featureState, err := r.client.GetCachedEnvironmentFeatureState(ctx, environment.APIKey, "", featureID)
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to get feature state of environment %q, got error: %s", environment.Name, err))
+			return diags
+		}
+		featureSegments, err := r.client.GetFeatureSegments(environment.ID, featureID)
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to get segment overrides of environment %q, got error: %s", environment.Name, err))
+			return diags
+		}
+		state := MakeEnvironmentFeatureStateDataFromClientFS(featureState)
+		if sensitive {
+			state.FeatureStateValue.MarkSensitive()
+		}
+		states[environment.Name] = FeatureEnvironmentStateData{
+			Enabled:              state.Enabled,
+			FeatureStateValue:    state.FeatureStateValue,
+			SegmentOverrideCount: types.Int64Value(int64(len(featureSegments))),
+		}
+	}
+	data.EnvironmentStates, diags = types.MapValueFrom(ctx, featureEnvironmentStateType, states)
+	return diags
+}
+
 func (r *featureResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data FeatureResourceData
 
@@ -144,11 +954,30 @@ func (r *featureResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	r.validateRules(ctx, getFeatureRulesData(ctx, req.Config.GetAttribute, &resp.Diagnostics), nil, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	config := data
+	if err := r.resolveReferences(&data); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve feature owners and tags, got error: %s", err))
+		return
+	}
+	if data.InitialFeatureStateValue != nil {
+		data.InitialValue = types.StringValue(data.InitialFeatureStateValue.String())
+	}
+
+	clientMetadata, err := r.clientMetadata(&data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve feature metadata, got error: %s", err))
+		return
+	}
+
 	clientFeature := data.ToClientFeature()
 
 	// Create the feature - owners and group_owners are sent in the request body
 	// and the API handles them during creation
-	err := r.client.CreateFeature(clientFeature)
+	err = r.client.CreateFeatureWithMetadata(clientFeature, clientMetadata)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create feature, got error: %s", err))
@@ -164,6 +993,33 @@ func (r *featureResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 
 	resourceData := MakeFeatureResourceDataFromClientFeature(clientFeature)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("deletion_mode"), &resourceData.DeletionMode)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("create_missing_tags"), &resourceData.CreateMissingTags)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("tags_authoritative"), &resourceData.TagsAuthoritative)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("owners_authoritative"), &resourceData.OwnersAuthoritative)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("include_environment_states"), &resourceData.IncludeEnvironmentStates)...)
+	if err := r.normaliseReferences(&resourceData, &config); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature owners and tags, got error: %s", err))
+		return
+	}
+	keepManagedReferences(&resourceData, &config)
+	resourceData.EnvironmentValues = config.EnvironmentValues
+	resourceData.Metadata = config.Metadata
+	if config.InitialFeatureStateValue != nil {
+		resourceData.InitialFeatureStateValue = config.InitialFeatureStateValue
+		resp.Diagnostics.Append(r.applyInitialFeatureStateValue(ctx, &resourceData)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	resp.Diagnostics.Append(r.applyEnvironmentValues(ctx, &resourceData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.readEnvironmentStates(ctx, &resourceData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	diags = resp.State.Set(ctx, &resourceData)
 	resp.Diagnostics.Append(diags...)
@@ -197,6 +1053,52 @@ func (r *featureResource) Read(ctx context.Context, req resource.ReadRequest, re
 		feature.GroupOwners = nil
 	}
 	resourceData := MakeFeatureResourceDataFromClientFeature(feature)
+	resourceData.DeletionMode = data.DeletionMode
+	resourceData.CreateMissingTags = data.CreateMissingTags
+	resourceData.TagsAuthoritative = data.TagsAuthoritative
+	resourceData.OwnersAuthoritative = data.OwnersAuthoritative
+	resourceData.EnvironmentValues = data.EnvironmentValues
+	resourceData.IncludeEnvironmentStates = data.IncludeEnvironmentStates
+	// The initial value only applies on creation, so keep the typed one
+	// Flagsmith can not tell apart from its inferred type
+	if data.InitialFeatureStateValue != nil && data.InitialFeatureStateValue.String() == feature.InitialValue {
+		resourceData.InitialFeatureStateValue = data.InitialFeatureStateValue
+	}
+	// Imported features and the ones created before deletion_mode existed
+	// have none
+	if resourceData.DeletionMode.IsNull() {
+		resourceData.DeletionMode = types.StringValue(FeatureDeletionModeDelete)
+	}
+	if resourceData.CreateMissingTags.IsNull() {
+		resourceData.CreateMissingTags = types.BoolValue(false)
+	}
+	if resourceData.TagsAuthoritative.IsNull() {
+		resourceData.TagsAuthoritative = types.BoolValue(true)
+	}
+	if resourceData.OwnersAuthoritative.IsNull() {
+		resourceData.OwnersAuthoritative = types.BoolValue(true)
+	}
+	if resourceData.IncludeEnvironmentStates.IsNull() {
+		resourceData.IncludeEnvironmentStates = types.BoolValue(false)
+	}
+	if err := r.normaliseReferences(&resourceData, &data); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature owners and tags, got error: %s", err))
+		return
+	}
+	keepManagedReferences(&resourceData, &data)
+	resourceData.Metadata = data.Metadata
+	if err := r.readMetadata(&resourceData); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature metadata, got error: %s", err))
+		return
+	}
+	resp.Diagnostics.Append(r.readEnvironmentValues(ctx, &resourceData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.readEnvironmentStates(ctx, &resourceData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	diags = resp.State.Set(ctx, &resourceData)
 	resp.Diagnostics.Append(diags...)
@@ -223,15 +1125,72 @@ func (r *featureResource) Update(ctx context.Context, req resource.UpdateRequest
 		tflog.Error(ctx, "Update: Error reading state data")
 		return
 	}
+	config := plan
+	if err := r.resolveReferences(&plan); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve feature owners and tags, got error: %s", err))
+		return
+	}
+	if err := r.resolveReferences(&state); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve feature owners and tags, got error: %s", err))
+		return
+	}
+
+	clientMetadata, err := r.clientMetadata(&plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve feature metadata, got error: %s", err))
+		return
+	}
+
+	// Switching to non-authoritative, the state may hold tags and owners
+	// attached otherwise, so only the planned ones are left to detach
+	if !plan.TagsAuthoritative.ValueBool() && !state.TagsAuthoritative.Equal(types.BoolValue(false)) {
+		state.Tags = keepManaged(state.Tags, plan.Tags)
+	}
+	if !plan.OwnersAuthoritative.ValueBool() && !state.OwnersAuthoritative.Equal(types.BoolValue(false)) {
+		state.Owners = keepManaged(state.Owners, plan.Owners)
+		state.GroupOwners = keepManaged(state.GroupOwners, plan.GroupOwners)
+	}
+	// Owners not managed so far are added by diffing against none
+	if !plan.OwnersAuthoritative.ValueBool() {
+		if state.Owners == nil && plan.Owners != nil {
+			state.Owners = &[]types.Int64{}
+		}
+		if state.GroupOwners == nil && plan.GroupOwners != nil {
+			state.GroupOwners = &[]types.Int64{}
+		}
+	}
+
 	// Generate API request body from plan
 	clientFeature := plan.ToClientFeature()
 	stateFeature := state.ToClientFeature()
 
+	// Only attach and detach the planned tags, keeping the ones attached
+	// otherwise
+	if !plan.TagsAuthoritative.ValueBool() {
+		unlock := r.client.LockFeatureTags(clientFeature.UUID)
+		defer unlock()
+		current, err := r.client.GetFeature(clientFeature.UUID)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature tags, got error: %s", err))
+			return
+		}
+		clientFeature.Tags = MergeIDs(current.Tags, clientFeature.Tags, Difference(&stateFeature.Tags, &clientFeature.Tags))
+	}
+
+	// Without authority over every owner, whether the last ones are removed
+	// is only known from the current owners of the feature
+	if !plan.OwnersAuthoritative.ValueBool() {
+		resp.Diagnostics.Append(r.validateRemainingOwners(plan.ProjectUUID.ValueString(), clientFeature, stateFeature)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Save planned owners before UpdateFeature mutates clientFeature via API response
 	planOwners := clientFeature.Owners
 	planGroupOwners := clientFeature.GroupOwners
 
-	err := r.client.UpdateFeature(clientFeature)
+	err = r.client.UpdateFeatureWithMetadata(clientFeature, clientMetadata)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update feature, got error: %s", err))
 		return
@@ -290,6 +1249,29 @@ func (r *featureResource) Update(ctx context.Context, req resource.UpdateRequest
 		feature.GroupOwners = nil
 	}
 	resourceData := MakeFeatureResourceDataFromClientFeature(feature)
+	resourceData.DeletionMode = plan.DeletionMode
+	resourceData.CreateMissingTags = plan.CreateMissingTags
+	resourceData.TagsAuthoritative = plan.TagsAuthoritative
+	resourceData.OwnersAuthoritative = plan.OwnersAuthoritative
+	resourceData.IncludeEnvironmentStates = plan.IncludeEnvironmentStates
+	if plan.InitialFeatureStateValue != nil && plan.InitialFeatureStateValue.String() == feature.InitialValue {
+		resourceData.InitialFeatureStateValue = plan.InitialFeatureStateValue
+	}
+	if err := r.normaliseReferences(&resourceData, &config); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature owners and tags, got error: %s", err))
+		return
+	}
+	keepManagedReferences(&resourceData, &config)
+	resourceData.EnvironmentValues = plan.EnvironmentValues
+	resourceData.Metadata = plan.Metadata
+	resp.Diagnostics.Append(r.applyEnvironmentValues(ctx, &resourceData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.readEnvironmentStates(ctx, &resourceData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Update the state with the new values
 	diags = resp.State.Set(ctx, &resourceData)
@@ -311,10 +1293,26 @@ func (r *featureResource) Delete(ctx context.Context, req resource.DeleteRequest
 	// Generate API request body from plan
 	clientFeature := state.ToClientFeature()
 
-	err := r.client.DeleteFeature(*clientFeature.ProjectID, *clientFeature.ID)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete feature, got error: %s", err))
+	switch state.DeletionMode.ValueString() {
+	case FeatureDeletionModePrevent:
+		resp.Diagnostics.AddError(
+			"Feature Deletion Prevented",
+			fmt.Sprintf("Feature %q has deletion_mode set to %q and can not be destroyed. Set deletion_mode to %q or %q and apply before destroying it, or remove it from the state with `terraform state rm`.",
+				state.Name.ValueString(), FeatureDeletionModePrevent, FeatureDeletionModeDelete, FeatureDeletionModeArchive),
+		)
 		return
+	case FeatureDeletionModeArchive:
+		err := r.client.ArchiveFeature(state.UUID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to archive feature, got error: %s", err))
+			return
+		}
+	default:
+		err := r.client.DeleteFeature(*clientFeature.ProjectID, *clientFeature.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete feature, got error: %s", err))
+			return
+		}
 	}
 	resp.State.RemoveResource(ctx)
 
@@ -322,3 +1320,73 @@ func (r *featureResource) Delete(ctx context.Context, req resource.DeleteRequest
 func (r *featureResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("uuid"), req, resp)
 }
+
+// UpgradeState upgrades the states of the features created before
+// initial_feature_state_value existed, inferring it from initial_value
+func (r *featureResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   featureSchemaV0(),
+			StateUpgrader: upgradeFeatureStateV0,
+		},
+	}
+}
+
+// featureSchemaV0 is the schema of the features as released before the
+// schema was versioned. It must not change.
+func featureSchemaV0() *schema.Schema {
+	return &schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id":              schema.Int64Attribute{Computed: true},
+			"uuid":            schema.StringAttribute{Computed: true},
+			"project_id":      schema.Int64Attribute{Computed: true},
+			"feature_name":    schema.StringAttribute{Required: true},
+			"type":            schema.StringAttribute{Optional: true, Computed: true},
+			"default_enabled": schema.BoolAttribute{Optional: true, Computed: true},
+			"initial_value":   schema.StringAttribute{Optional: true, Computed: true},
+			"description":     schema.StringAttribute{Optional: true},
+			"is_archived":     schema.BoolAttribute{Optional: true, Computed: true},
+			"owners":          schema.SetAttribute{Optional: true, ElementType: types.Int64Type},
+			"group_owners":    schema.SetAttribute{Optional: true, ElementType: types.Int64Type},
+			"tags":            schema.SetAttribute{Optional: true, ElementType: types.Int64Type},
+			"project_uuid":    schema.StringAttribute{Required: true},
+		},
+	}
+}
+
+// featureDefaultsV1 holds the defaults of the attributes added in version 1
+// of the schema, set on the upgraded states so they plan no change
+var featureDefaultsV1 = map[string]tftypes.Value{
+	"create_missing_tags":        tftypes.NewValue(tftypes.Bool, false),
+	"tags_authoritative":         tftypes.NewValue(tftypes.Bool, true),
+	"owners_authoritative":       tftypes.NewValue(tftypes.Bool, true),
+	"include_environment_states": tftypes.NewValue(tftypes.Bool, false),
+	"deletion_mode":              tftypes.NewValue(tftypes.String, FeatureDeletionModeDelete),
+}
+
+func upgradeFeatureStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var priorValues map[string]tftypes.Value
+	if err := req.State.Raw.As(&priorValues); err != nil {
+		resp.Diagnostics.AddError("Unable to Upgrade Resource State", fmt.Sprintf("Unable to read the prior state, got error: %s", err))
+		return
+	}
+	stateType := resp.State.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range stateType.AttributeTypes {
+		if value, ok := priorValues[name]; ok {
+			values[name] = value
+		} else if value, ok := featureDefaultsV1[name]; ok {
+			values[name] = value
+		} else {
+			values[name] = tftypes.NewValue(attributeType, nil)
+		}
+	}
+	resp.State.Raw = tftypes.NewValue(stateType, values)
+
+	var initialValue types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("initial_value"), &initialValue)...)
+	if resp.Diagnostics.HasError() || initialValue.IsNull() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("initial_feature_state_value"), MakeInitialFeatureStateValue(initialValue.ValueString()))...)
+}
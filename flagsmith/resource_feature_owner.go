@@ -0,0 +1,243 @@
+package flagsmith
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Kinds of owners of a feature, as found in the import identifiers
+const (
+	featureOwnerTypeUser  = "user"
+	featureOwnerTypeGroup = "group"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &featureOwnerResource{}
+var _ resource.ResourceWithImportState = &featureOwnerResource{}
+var _ resource.ResourceWithConfigValidators = &featureOwnerResource{}
+
+func newFeatureOwnerResource() resource.Resource {
+	return &featureOwnerResource{}
+}
+
+type featureOwnerResource struct {
+	client *fsClient
+}
+
+func (r *featureOwnerResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_feature_owner"
+}
+
+func (r *featureOwnerResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*fsClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *featureOwnerResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Adds a user or a group to the owners of a feature, leaving its other owners untouched. " +
+			"The flagsmith_feature resource of the feature should set `owners_authoritative = false`. " +
+			"Destroying it is not checked against `enforce_feature_owners` and may leave the feature without owners.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the ownership, in the format `feature_uuid,user,user_id` or `feature_uuid,group,group_id`",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"feature_uuid": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "UUID of the feature",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"user_id": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "ID of the user owning the feature. NOTE: Conflicts with group_id",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+			},
+			"group_id": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "ID of the group owning the feature. NOTE: Conflicts with user_id",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+			},
+		},
+	}
+}
+
+func (r *featureOwnerResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("user_id"),
+			path.MatchRoot("group_id"),
+		),
+	}
+}
+
+// owner returns the kind and the ID of the owner
+func (d *FeatureOwnerResourceData) owner() (string, int64) {
+	if !d.GroupID.IsNull() {
+		return featureOwnerTypeGroup, d.GroupID.ValueInt64()
+	}
+	return featureOwnerTypeUser, d.UserID.ValueInt64()
+}
+
+func (r *featureOwnerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data FeatureOwnerResourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	feature, err := r.client.GetFeature(data.FeatureUUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature, got error: %s", err))
+		return
+	}
+	ownerType, ownerID := data.owner()
+	if ownerType == featureOwnerTypeGroup {
+		err = r.client.AddFeatureGroupOwners(feature, []int64{ownerID})
+	} else {
+		err = r.client.AddFeatureOwners(feature, []int64{ownerID})
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to add feature owner, got error: %s", err))
+		return
+	}
+	resourceData := MakeFeatureOwnerResourceData(data.FeatureUUID.ValueString(), ownerType, ownerID)
+
+	diags = resp.State.Set(ctx, &resourceData)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *featureOwnerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data FeatureOwnerResourceData
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	// Early return if the state is wrong
+	if diags.HasError() {
+		return
+	}
+
+	feature, err := r.client.GetFeature(data.FeatureUUID.ValueString())
+	if err != nil {
+		if _, ok := err.(flagsmithapi.FeatureNotFoundError); ok {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature, got error: %s", err))
+		return
+	}
+	ownerType, ownerID := data.owner()
+	owners := feature.Owners
+	if ownerType == featureOwnerTypeGroup {
+		owners = feature.GroupOwners
+	}
+	// The owner was removed from the feature or deleted
+	if owners == nil || !slices.Contains(*owners, ownerID) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resourceData := MakeFeatureOwnerResourceData(data.FeatureUUID.ValueString(), ownerType, ownerID)
+
+	diags = resp.State.Set(ctx, &resourceData)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update only carries the plan over, since all the attributes require a
+// replacement
+func (r *featureOwnerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan FeatureOwnerResourceData
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Update: Error reading plan data")
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *featureOwnerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Get current state
+	var state FeatureOwnerResourceData
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Delete: Error reading state data")
+		return
+	}
+
+	feature, err := r.client.GetFeature(state.FeatureUUID.ValueString())
+	if err != nil {
+		// Deleted features have no owners left to remove
+		if _, ok := err.(flagsmithapi.FeatureNotFoundError); ok {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature, got error: %s", err))
+		return
+	}
+	ownerType, ownerID := state.owner()
+	if ownerType == featureOwnerTypeGroup {
+		err = r.client.RemoveFeatureGroupOwners(feature, []int64{ownerID})
+	} else {
+		err = r.client.RemoveFeatureOwners(feature, []int64{ownerID})
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to remove feature owner, got error: %s", err))
+		return
+	}
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *featureOwnerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importKey := strings.Split(req.ID, ",")
+	if len(importKey) != 3 || importKey[0] == "" || (importKey[1] != featureOwnerTypeUser && importKey[1] != featureOwnerTypeGroup) {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: feature_uuid,user,user_id or feature_uuid,group,group_id Got: %q", req.ID),
+		)
+		return
+	}
+	ownerID, err := strconv.ParseInt(importKey[2], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Unexpected Import Identifier", fmt.Sprintf("%s_id must be an integer, got: %q", importKey[1], importKey[2]))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("feature_uuid"), importKey[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(importKey[1]+"_id"), ownerID)...)
+}
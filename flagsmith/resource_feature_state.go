@@ -4,19 +4,24 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/Flagsmith/flagsmith-go-api-client"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
@@ -25,13 +30,15 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces
 var _ resource.Resource = &featureStateResource{}
 var _ resource.ResourceWithImportState = &featureStateResource{}
+var _ resource.ResourceWithModifyPlan = &featureStateResource{}
+var _ resource.ResourceWithValidateConfig = &featureStateResource{}
 
 func newFeatureStateResource() resource.Resource {
 	return &featureStateResource{}
 }
 
 type featureStateResource struct {
-	client *flagsmithapi.Client
+	client *fsClient
 }
 
 func (r *featureStateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -44,11 +51,11 @@ func (r *featureStateResource) Configure(ctx context.Context, req resource.Confi
 		return
 	}
 
-	client, ok := req.ProviderData.(*flagsmithapi.Client)
+	client, ok := req.ProviderData.(*fsClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *flagsmithapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -58,7 +65,7 @@ func (r *featureStateResource) Configure(ctx context.Context, req resource.Confi
 func (t *featureStateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
-		MarkdownDescription: "Flagsmith Feature state/ Remote config value",
+		MarkdownDescription: "Flagsmith Feature state/ Remote config value of a feature in an environment. Use `flagsmith_segment_override` to manage segment overrides.",
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
@@ -81,43 +88,12 @@ func (t *featureStateResource) Schema(ctx context.Context, req resource.SchemaRe
 				Required:            true,
 				PlanModifiers:       []planmodifier.Int64{int64planmodifier.RequiresReplace()},
 			},
-			"feature_state_value": schema.SingleNestedAttribute{
-				Required: true,
-				MarkdownDescription: "Value for the feature State. NOTE: One of string_value, integer_value or boolean_value must be set",
-				Attributes: map[string]schema.Attribute{
-					"type": schema.StringAttribute{
-						MarkdownDescription: "Type of the feature state value, can be `unicode`, `int` or `bool`",
-						Required:            true,
-						Validators: []validator.String{
-							stringvalidator.OneOf([]string{"unicode", "int", "bool"}...),
-						},
-					},
-					"string_value": schema.StringAttribute{
-						MarkdownDescription: "String value of the feature if the type is `unicode`.",
-						Optional:            true,
-						Validators: []validator.String{
-							// Validate string value satisfies the regular expression for no leading or trailing whitespace
-							// but allow empty string
-							stringvalidator.RegexMatches(
-								regexp.MustCompile(`^\S[\s\S]*\S$|^$`),
-								"Leading and trailing whitespace is not allowed",
-							),
-						},
-					},
-					"integer_value": schema.Int64Attribute{
-						MarkdownDescription: "Integer value of the feature if the type is `int`",
-						Optional:            true,
-					},
-					"boolean_value": schema.BoolAttribute{
-						MarkdownDescription: "Boolean value of the feature if the type is `bool`",
-						Optional:            true,
-					},
-				},
-			},
+			"feature_state_value": managedFeatureStateValueSchema(),
 
 			"enabled": schema.BoolAttribute{
-				MarkdownDescription: "Used for enabling/disabling the feature",
-				Required:            true,
+				MarkdownDescription: "Used for enabling/disabling the feature. Required unless the feature state exists and `enabled` is left out of `managed_fields`, in which case it is read from Flagsmith",
+				Optional:            true,
+				Computed:            true,
 			},
 			"environment_id": schema.Int64Attribute{
 				MarkdownDescription: "ID of the environment",
@@ -127,11 +103,13 @@ func (t *featureStateResource) Schema(ctx context.Context, req resource.SchemaRe
 			"segment_id": schema.Int64Attribute{
 				MarkdownDescription: "ID of the segment, used for creating segment overrides",
 				Optional:            true,
+				DeprecationMessage:  "Use the `flagsmith_segment_override` resource to manage segment overrides.",
 			},
 			"segment_priority": schema.Int64Attribute{
 				MarkdownDescription: "Priority of the segment overrides.",
 				Optional:            true,
 				Computed:            true,
+				DeprecationMessage:  "Use the `flagsmith_segment_override` resource to manage segment overrides.",
 			},
 			"feature_segment_id": schema.Int64Attribute{
 				MarkdownDescription: "ID of the feature_segment, used internally to bind a feature state to a segment",
@@ -139,39 +117,393 @@ func (t *featureStateResource) Schema(ctx context.Context, req resource.SchemaRe
 
 				PlanModifiers: []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
 			},
+			"change_request_mode": schema.StringAttribute{
+				MarkdownDescription: "Overrides the `change_request_mode` of the provider for this feature state. `never` writes it directly, `always` opens a change request instead and `auto` opens one only if the environment requires approvals. Segment overrides are always written directly, and are refused during the plan if the mode is `auto` or `always` and the environment requires approvals",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(changeRequestModes...),
+				},
+			},
+			"change_request_id": schema.Int64Attribute{
+				MarkdownDescription: "ID of the change request opened by the last apply, as long as it has not been committed",
+				Computed:            true,
+			},
+			"change_request_status": schema.StringAttribute{
+				MarkdownDescription: "Status of the last change request opened for this feature state, one of `pending_approval`, `approved`, `committed` or `deleted`",
+				Computed:            true,
+			},
+			"live_from": schema.StringAttribute{
+				MarkdownDescription: "RFC3339 timestamp at which the feature state goes live. If it is in the future, a scheduled change is created instead of updating the feature state immediately. Not supported for segment overrides",
+				Optional:            true,
+				Validators: []validator.String{
+					rfc3339Validator{},
+				},
+			},
+			"scheduled_change_id": schema.Int64Attribute{
+				MarkdownDescription: "ID of the scheduled change created by the last apply, as long as it has not gone live",
+				Computed:            true,
+			},
+			"feature_version_uuid": schema.StringAttribute{
+				MarkdownDescription: "UUID of the feature version published by the last apply, if the environment uses v2 feature versioning",
+				Computed:            true,
+			},
+			"managed_fields": schema.SetAttribute{
+				MarkdownDescription: "Fields of the feature state owned by Terraform, a subset of `enabled` and `value`. The other fields are only written on create and changes made to them outside of Terraform(e.g: in the Flagsmith dashboard) are kept. The other fields are read from Flagsmith, and can be left unset once the feature state exists. Defaults to all the fields",
+				Optional:            true,
+				ElementType:         types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.OneOf(managedFields...)),
+				},
+			},
 		},
 	}
 }
 
+// computedFeatureStateValueResourceSchema returns the schema of a feature state
+// value read by a resource
+func computedFeatureStateValueResourceSchema(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: description,
+		Computed:            true,
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Type of the feature state value, can be `unicode`, `int` or `bool`",
+				Computed:            true,
+			},
+			"string_value": schema.StringAttribute{
+				MarkdownDescription: "String value of the feature if the type is `unicode` and the feature is not sensitive",
+				Computed:            true,
+			},
+			"sensitive_string_value": schema.StringAttribute{
+				MarkdownDescription: "String value of the feature if the type is `unicode` and the feature is tagged as sensitive",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"integer_value": schema.Int64Attribute{
+				MarkdownDescription: "Integer value of the feature if the type is `int`",
+				Computed:            true,
+			},
+			"boolean_value": schema.BoolAttribute{
+				MarkdownDescription: "Boolean value of the feature if the type is `bool`",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// featureStateValueSchema returns the schema of the typed `feature_state_value`
+// attribute shared by the resources that manage a feature state.
+func featureStateValueSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Required:            true,
+		MarkdownDescription: "Value for the feature State. NOTE: One of string_value, sensitive_string_value, integer_value or boolean_value must be set",
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Type of the feature state value, can be `unicode`, `int` or `bool`",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{"unicode", "int", "bool"}...),
+				},
+			},
+			"string_value": schema.StringAttribute{
+				MarkdownDescription: "String value of the feature if the type is `unicode`.",
+				Optional:            true,
+				Validators: []validator.String{
+					// Validate string value satisfies the regular expression for no leading or trailing whitespace
+					// but allow empty string
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^\S[\s\S]*\S$|^$`),
+						"Leading and trailing whitespace is not allowed",
+					),
+				},
+			},
+			"sensitive_string_value": schema.StringAttribute{
+				MarkdownDescription: "String value of the feature if the type is `unicode`, hidden from the plan output. Use it instead of `string_value` for secrets",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^\S[\s\S]*\S$|^$`),
+						"Leading and trailing whitespace is not allowed",
+					),
+				},
+			},
+			"integer_value": schema.Int64Attribute{
+				MarkdownDescription: "Integer value of the feature if the type is `int`",
+				Optional:            true,
+			},
+			"boolean_value": schema.BoolAttribute{
+				MarkdownDescription: "Boolean value of the feature if the type is `bool`",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// managedFeatureStateValueSchema returns the schema of the
+// `feature_state_value` of a flagsmith_feature_state, which can be left unset
+// once the value is no longer managed
+func managedFeatureStateValueSchema() schema.SingleNestedAttribute {
+	attribute := featureStateValueSchema()
+	attribute.Required = false
+	attribute.Optional = true
+	attribute.Computed = true
+	attribute.MarkdownDescription += ". Required unless the feature state exists and `value` is left out of `managed_fields`, in which case it is read from Flagsmith"
+	return attribute
+}
+
+// ValidateConfig requires exactly one of the values of feature_state_value,
+// if set
+func (f *featureStateResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var value types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("feature_state_value"), &value)...)
+	if resp.Diagnostics.HasError() || value.IsNull() {
+		return
+	}
+	var exactlyOneResp resource.ValidateConfigResponse
+	resourcevalidator.ExactlyOneOf(
+		path.MatchRoot("feature_state_value").AtName("string_value"),
+		path.MatchRoot("feature_state_value").AtName("sensitive_string_value"),
+		path.MatchRoot("feature_state_value").AtName("integer_value"),
+		path.MatchRoot("feature_state_value").AtName("boolean_value"),
+	).ValidateResource(ctx, req, &exactlyOneResp)
+	resp.Diagnostics.Append(exactlyOneResp.Diagnostics...)
+}
+
 func (f *featureStateResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
     return []resource.ConfigValidator{
-        resourcevalidator.ExactlyOneOf(
-            path.MatchRoot("feature_state_value").AtName("string_value"),
-            path.MatchRoot("feature_state_value").AtName("integer_value"),
-            path.MatchRoot("feature_state_value").AtName("boolean_value"),
+        resourcevalidator.Conflicting(
+            path.MatchRoot("segment_id"),
+            path.MatchRoot("live_from"),
         ),
     }
 }
 
+// validateSensitiveFeatureStateValue errors if the string value of a feature
+// carrying the `sensitive_feature_tag` of the provider is not set with
+// `sensitive_string_value`
+func validateSensitiveFeatureStateValue(client *fsClient, environmentKey types.String, featureID types.Int64, value *FeatureStateValue) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if client == nil || value == nil || value.StringValue.IsNull() || environmentKey.IsUnknown() || featureID.IsUnknown() {
+		return diags
+	}
+	sensitive, err := client.IsSensitiveEnvironmentFeature(environmentKey.ValueString(), featureID.ValueInt64())
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read the tags of the feature, got error: %s", err))
+		return diags
+	}
+	if sensitive {
+		diags.AddAttributeError(
+			path.Root("feature_state_value").AtName("string_value"),
+			"Sensitive Feature Value",
+			fmt.Sprintf("Feature %d is tagged %q, set its value with sensitive_string_value instead", featureID.ValueInt64(), client.sensitiveFeatureTag),
+		)
+	}
+	return diags
+}
+
+// ModifyPlan keeps the current values of the unmanaged fields left unset,
+// validates the value of the plan and that no other resource manages the
+// feature state
+func (r *featureStateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is planned on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	resp.Diagnostics.Append(planUnmanagedFields(ctx, req, resp)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var plan FeatureStateResourceData
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateSensitiveFeatureStateValue(r.client, plan.EnvironmentKey, plan.Feature, plan.FeatureStateValue)...)
+	resp.Diagnostics.Append(r.claimFeatureState(&plan)...)
+	resp.Diagnostics.Append(r.validateSegmentOverrideChangeRequestMode(&plan)...)
+}
+
+// planUnmanagedFields plans the current values of the fields left out of
+// `managed_fields` and unset in the configuration. The managed fields, and
+// every field of a new feature state, must be set.
+func planUnmanagedFields(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var managedFields types.Set
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("managed_fields"), &managedFields)...)
+	if diags.HasError() || managedFields.IsUnknown() {
+		return diags
+	}
+	data := FeatureStateResourceData{ManagedFields: managedFields}
+	fields := []struct {
+		field     string
+		attribute path.Path
+	}{
+		{ManagedFieldEnabled, path.Root("enabled")},
+		{ManagedFieldValue, path.Root("feature_state_value")},
+	}
+	for _, field := range fields {
+		var configured, current attr.Value
+		diags.Append(req.Config.GetAttribute(ctx, field.attribute, &configured)...)
+		if !req.State.Raw.IsNull() {
+			diags.Append(req.State.GetAttribute(ctx, field.attribute, &current)...)
+		}
+		if diags.HasError() {
+			return diags
+		}
+		managed := data.Manages(field.field)
+		switch {
+		case configured.IsNull() && (current == nil || managed):
+			diags.AddAttributeError(field.attribute, "Missing Feature State Field",
+				fmt.Sprintf("%s must be set, unless the feature state exists and %q is left out of managed_fields", field.attribute, field.field))
+		case configured.IsNull():
+			diags.Append(resp.Plan.SetAttribute(ctx, field.attribute, current)...)
+		case current != nil && !managed && !configured.IsUnknown() && !configured.Equal(current):
+			diags.AddAttributeWarning(field.attribute, "Unmanaged Feature State Field",
+				fmt.Sprintf("%q is left out of managed_fields, so the configured %s is not written to Flagsmith. Remove it from the configuration to keep the value set in Flagsmith.", field.field, field.attribute))
+		}
+	}
+	return diags
+}
+
+// validateSegmentOverrideChangeRequestMode errors for the segment overrides
+// whose change_request_mode asks for change requests in an environment
+// requiring approvals, since segment overrides can only be written directly
+func (r *featureStateResource) validateSegmentOverrideChangeRequestMode(data *FeatureStateResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if r.client == nil || data.Segment.IsUnknown() || data.Segment.ValueInt64() == 0 || data.EnvironmentKey.IsUnknown() || data.ChangeRequestMode.IsUnknown() {
+		return diags
+	}
+	mode := data.ChangeRequestMode.ValueString()
+	if mode == "" {
+		mode = r.client.changeRequestMode
+	}
+	if mode != ChangeRequestModeAuto && mode != ChangeRequestModeAlways {
+		return diags
+	}
+	environment, err := r.client.GetEnvironment(data.EnvironmentKey.ValueString())
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read environment, got error: %s", err))
+		return diags
+	}
+	if environment.MinimumChangeRequestApprovals > 0 {
+		diags.AddAttributeError(
+			path.Root("change_request_mode"),
+			"Change Request Not Supported",
+			fmt.Sprintf("Segment overrides can not be written through change requests, and environment %q requires %d approvals with change_request_mode %q. Manage the segment override outside of Terraform, or set change_request_mode to %q to write it directly.",
+				data.EnvironmentKey.ValueString(), environment.MinimumChangeRequestApprovals, mode, ChangeRequestModeNever),
+		)
+	}
+	return diags
+}
+
+// currentFeatureState reads the live feature state, or segment override, of
+// the resource from Flagsmith
+func (r *featureStateResource) currentFeatureState(data *FeatureStateResourceData) (*flagsmithapi.FeatureState, error) {
+	environmentKey := data.EnvironmentKey.ValueString()
+	if data.FeatureSegment.ValueInt64() == 0 {
+		return r.client.GetEnvironmentFeatureState(environmentKey, data.Feature.ValueInt64())
+	}
+	versioned, err := r.client.IsV2Versioned(environmentKey)
+	if err != nil {
+		return nil, err
+	}
+	if versioned {
+		return r.client.GetSegmentOverride(data.Environment.ValueInt64(), data.Feature.ValueInt64(), data.Segment.ValueInt64())
+	}
+	return r.client.GetFeatureState(data.UUID.ValueString())
+}
+
+// claimFeatureState errors if the environment feature state is also managed
+// by the environment_values of its flagsmith_feature
+func (r *featureStateResource) claimFeatureState(data *FeatureStateResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if r.client == nil || data.Segment.ValueInt64() != 0 || data.EnvironmentKey.IsUnknown() || data.Feature.IsUnknown() {
+		return diags
+	}
+	err := r.client.ClaimFeatureState(data.EnvironmentKey.ValueString(), data.Feature.ValueInt64(), featureStateManagerFeatureState)
+	if err != nil {
+		diags.AddAttributeError(path.Root("feature_id"), "Feature State Managed Twice", err.Error())
+	}
+	return diags
+}
+
+// publishFeatureState applies the changes of the given feature version to a
+// versioned environment and reads the feature state back, which is the segment
+// override of segmentID if set
+func (r *featureStateResource) publishFeatureState(environmentKey string, featureID int64, segmentID *int64, version *FeatureVersion) (*flagsmithapi.FeatureState, error) {
+	environment, err := r.client.GetEnvironment(environmentKey)
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.PublishFeatureVersion(environment.ID, featureID, version)
+	if err != nil {
+		return nil, err
+	}
+	if segmentID != nil {
+		return r.client.GetSegmentOverride(environment.ID, featureID, *segmentID)
+	}
+	return r.client.GetEnvironmentFeatureState(environmentKey, featureID)
+}
+
 func (r *featureStateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data FeatureStateResourceData
 
 	diags := req.Config.Get(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.claimFeatureState(&data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 	// Create segment override if segment is set
 	if data.Segment.ValueInt64() != 0 {
+		unlock := r.client.LockSegmentOverrides(data.EnvironmentKey.ValueString(), data.Feature.ValueInt64())
+		defer unlock()
+
 		clientFeatureState := data.ToClientFS()
-		err := r.client.CreateSegmentOverride(clientFeatureState)
+		versioned, err := r.client.IsV2Versioned(data.EnvironmentKey.ValueString())
 		if err != nil {
-			resp.Diagnostics.AddError("Error creating segment override", err.Error())
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment versioning, got error: %s", err))
 			return
 		}
+		var featureState *flagsmithapi.FeatureState
+		featureVersion := types.StringNull()
+		if versioned {
+			version := FeatureVersion{
+				FeatureStatesToCreate: []FeatureVersionFeatureState{NewFeatureVersionFeatureState(clientFeatureState)},
+			}
+			featureState, err = r.publishFeatureState(data.EnvironmentKey.ValueString(), data.Feature.ValueInt64(), clientFeatureState.Segment, &version)
+			if err != nil {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to publish segment override, got error: %s", err))
+				return
+			}
+			featureVersion = types.StringValue(version.UUID)
+		} else {
+			err = r.client.CreateSegmentOverride(clientFeatureState)
+			if err != nil {
+				resp.Diagnostics.AddError("Error creating segment override", err.Error())
+				return
+			}
+			// Read the override back to load the final priority assigned by Flagsmith
+			featureState, err = r.client.GetFeatureState(clientFeatureState.UUID)
+			if err != nil {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read segment override after create, got error: %s", err))
+				return
+			}
+		}
 		// set the state with the new values
-		resourceData := MakeFeatureStateResourceDataFromClientFS(clientFeatureState)
+		resourceData := MakeFeatureStateResourceDataFromClientFS(featureState)
+		resourceData.EnvironmentKey = data.EnvironmentKey
+		resourceData.FeatureVersion = featureVersion
+		resourceData.ChangeRequestMode = data.ChangeRequestMode
+		resourceData.ManagedFields = data.ManagedFields
+		if data.FeatureStateValue.IsSensitive() {
+			resourceData.FeatureStateValue.MarkSensitive()
+		}
 		diags = resp.State.Set(ctx, &resourceData)
 		resp.Diagnostics.Append(diags...)
 		return
@@ -193,14 +525,14 @@ func (r *featureStateResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	//Now call update to update the state
+	//Now call update to update the state, seeding the unmanaged fields from the config
 	updateResponse := resource.UpdateResponse{State: resp.State}
-	r.Update(ctx, resource.UpdateRequest{
+	r.update(ctx, resource.UpdateRequest{
 		Config:       req.Config,
 		Plan:         req.Plan,
 		State:        readResponse.State,
 		ProviderMeta: req.ProviderMeta,
-	}, &updateResponse)
+	}, &updateResponse, true)
 
 	if updateResponse.Diagnostics.HasError() {
 		resp.Diagnostics.Append(updateResponse.Diagnostics...)
@@ -222,32 +554,262 @@ func (r *featureStateResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
+	featureStateUUID := data.UUID.ValueString()
+
+	// The state holds the values of a pending change request until it gets committed
+	if !data.ChangeRequest.IsNull() && !data.ChangeRequest.IsUnknown() {
+		changeRequest, status, err := r.changeRequestStatus(data.EnvironmentKey.ValueString(), data.ChangeRequest.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read change request, got error: %s", err))
+			return
+		}
+		data.ChangeRequestStatus = types.StringValue(status)
+		if status == ChangeRequestPendingApproval || status == ChangeRequestApproved {
+			diags = resp.State.Set(ctx, &data)
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		data.ChangeRequest = types.Int64Null()
+		if status == ChangeRequestCommitted {
+			// Committing a change request creates a new version of the feature state
+			featureStateUUID = ""
+			if changeRequest.IsScheduled(time.Now()) {
+				data.ScheduledChange = types.Int64Value(changeRequest.ID)
+			}
+		}
+	}
+
+	// The state holds the values of a scheduled change until it goes live
+	if !data.ScheduledChange.IsNull() && !data.ScheduledChange.IsUnknown() {
+		changeRequest, _, err := r.changeRequestStatus(data.EnvironmentKey.ValueString(), data.ScheduledChange.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read scheduled change, got error: %s", err))
+			return
+		}
+		if changeRequest != nil && changeRequest.IsScheduled(time.Now()) {
+			diags = resp.State.Set(ctx, &data)
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		// The scheduled change either went live or has been cancelled
+		featureStateUUID = ""
+	}
+
+	// Every published version of a versioned environment has its own feature
+	// states, the live one is looked up by feature(and segment)
+	versioned, err := r.client.IsV2Versioned(data.EnvironmentKey.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment versioning, got error: %s", err))
+		return
+	}
+	if versioned {
+		featureStateUUID = ""
+	}
+
 	var featureState *flagsmithapi.FeatureState
-	var err error
 
-	if data.UUID.ValueString() != "" {
+	if data.FeatureSegment.ValueInt64() != 0 && versioned {
+		featureState, err = r.client.GetSegmentOverride(data.Environment.ValueInt64(), data.Feature.ValueInt64(), data.Segment.ValueInt64())
+		if _, ok := err.(FeatureSegmentNotFoundError); ok {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+	} else if data.FeatureSegment.ValueInt64() != 0 {
 		featureState, err = r.client.GetFeatureState(data.UUID.ValueString())
 
 	} else {
-		featureState, err = r.client.GetEnvironmentFeatureState(data.EnvironmentKey.ValueString(), data.Feature.ValueInt64())
+		// Served from the feature states of the environment fetched once for all the resources
+		featureState, err = r.client.GetCachedEnvironmentFeatureState(ctx, data.EnvironmentKey.ValueString(), featureStateUUID, data.Feature.ValueInt64())
 	}
 	if err != nil {
 		if _, ok := err.(flagsmithapi.FeatureStateNotFoundError); ok {
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		panic(err)
-
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature state, got error: %s", err))
+		return
 	}
 	resourceData := MakeFeatureStateResourceDataFromClientFS(featureState)
 
 	resourceData.EnvironmentKey = data.EnvironmentKey
+	resourceData.ChangeRequestMode = data.ChangeRequestMode
+	resourceData.LiveFrom = data.LiveFrom
+	resourceData.ManagedFields = data.ManagedFields
+
+	// A value set with sensitive_string_value stays sensitive, and so do the
+	// values of the features tagged as such
+	sensitive := data.FeatureStateValue.IsSensitive()
+	if !sensitive {
+		sensitive, err = r.client.IsSensitiveEnvironmentFeature(data.EnvironmentKey.ValueString(), data.Feature.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read the tags of the feature, got error: %s", err))
+			return
+		}
+	}
+	if sensitive {
+		resourceData.FeatureStateValue.MarkSensitive()
+	}
+	if !data.ChangeRequestStatus.IsUnknown() {
+		resourceData.ChangeRequestStatus = data.ChangeRequestStatus
+	}
+	if !data.FeatureVersion.IsUnknown() {
+		resourceData.FeatureVersion = data.FeatureVersion
+	}
 
 	diags = resp.State.Set(ctx, &resourceData)
 	resp.Diagnostics.Append(diags...)
 }
 
+// changeRequestStatus returns a change request opened for a feature state of
+// the given environment along with its status. The change request is nil if
+// it has been deleted.
+func (r *featureStateResource) changeRequestStatus(environmentKey string, changeRequestID int64) (*ChangeRequest, string, error) {
+	changeRequest, err := r.client.GetChangeRequest(changeRequestID)
+	if err != nil {
+		if _, ok := err.(ChangeRequestNotFoundError); ok {
+			return nil, ChangeRequestDeleted, nil
+		}
+		return nil, "", err
+	}
+	environment, err := r.client.GetEnvironment(environmentKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return changeRequest, changeRequest.Status(environment.MinimumChangeRequestApprovals), nil
+}
+
+// openChangeRequest opens a change request with the values of the plan instead
+// of updating the feature state, and records it in the state. Unless it
+// requires approval, or its environment requires approvals, the change request
+// is committed right away, which is how Flagsmith schedules a change.
+func (r *featureStateResource) openChangeRequest(ctx context.Context, plan, state *FeatureStateResourceData, requiresApproval bool, resp *resource.UpdateResponse) {
+	environmentKey := plan.EnvironmentKey.ValueString()
+
+	// Flagsmith refuses to commit change requests that lack the approvals
+	// their environment requires, so those are left open for approval
+	if !requiresApproval {
+		environment, err := r.client.GetEnvironment(environmentKey)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment, got error: %s", err))
+			return
+		}
+		requiresApproval = environment.MinimumChangeRequestApprovals > 0
+	}
+
+	environmentID := state.Environment.ValueInt64()
+	featureState := ChangeRequestFeatureState{
+		Enabled:           plan.Enabled.ValueBool(),
+		FeatureStateValue: plan.FeatureStateValue.ToClientFSV(),
+		Feature:           state.Feature.ValueInt64(),
+		Environment:       &environmentID,
+	}
+	title := fmt.Sprintf("Update feature %d in environment %s", plan.Feature.ValueInt64(), environmentKey)
+	scheduled := plan.IsScheduled(time.Now())
+	if scheduled {
+		liveFrom := plan.LiveFrom.ValueString()
+		featureState.LiveFrom = &liveFrom
+		title = fmt.Sprintf("Update feature %d in environment %s at %s", plan.Feature.ValueInt64(), environmentKey, liveFrom)
+	}
+	changeRequest := ChangeRequest{
+		Title: title,
+		Description: fmt.Sprintf("Opened by Terraform: set enabled to %t and the %s value to %s",
+			plan.Enabled.ValueBool(), plan.FeatureStateValue.Type.ValueString(), describeFeatureStateValue(plan.FeatureStateValue)),
+		FeatureStates: []ChangeRequestFeatureState{featureState},
+	}
+	err := r.client.CreateChangeRequest(environmentKey, &changeRequest)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create change request, got error: %s", err))
+		return
+	}
+
+	resourceData := *plan
+	resourceData.ID = state.ID
+	resourceData.UUID = state.UUID
+	resourceData.Environment = state.Environment
+	resourceData.FeatureSegment = state.FeatureSegment
+	resourceData.SegmentPriority = state.SegmentPriority
+	resourceData.ChangeRequest = types.Int64Null()
+	resourceData.ScheduledChange = types.Int64Null()
+
+	if requiresApproval {
+		tflog.Info(ctx, "Opened change request instead of updating the feature state", map[string]interface{}{"change_request_id": changeRequest.ID})
+		_, status, err := r.changeRequestStatus(environmentKey, changeRequest.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read change request, got error: %s", err))
+			return
+		}
+		resourceData.ChangeRequest = types.Int64Value(changeRequest.ID)
+		resourceData.ChangeRequestStatus = types.StringValue(status)
+	} else {
+		err = r.client.CommitChangeRequest(changeRequest.ID)
+		if err != nil {
+			// Do not leave behind a change request the state does not record
+			if deleteErr := r.client.DeleteChangeRequest(changeRequest.ID); deleteErr != nil {
+				err = fmt.Errorf("%s, and deleting change request %d failed with: %s", err, changeRequest.ID, deleteErr)
+			}
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to commit scheduled change, got error: %s", err))
+			return
+		}
+		tflog.Info(ctx, "Scheduled the feature state change", map[string]interface{}{"scheduled_change_id": changeRequest.ID})
+		resourceData.ScheduledChange = types.Int64Value(changeRequest.ID)
+		resourceData.ChangeRequestStatus = types.StringValue(ChangeRequestCommitted)
+	}
+
+	diags := resp.State.Set(ctx, &resourceData)
+	resp.Diagnostics.Append(diags...)
+}
+
+// withdrawChangeRequests deletes the pending change request and the scheduled
+// change recorded in the state, if any
+func (r *featureStateResource) withdrawChangeRequests(state *FeatureStateResourceData) error {
+	for _, pending := range []types.Int64{state.ChangeRequest, state.ScheduledChange} {
+		if pending.IsNull() || pending.IsUnknown() {
+			continue
+		}
+		err := r.client.DeleteChangeRequest(pending.ValueInt64())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func describeFeatureStateValue(value *FeatureStateValue) string {
+	if value.IsSensitive() {
+		return "(sensitive value)"
+	}
+	switch value.Type.ValueString() {
+	case "int":
+		return value.IntegerValue.String()
+	case "bool":
+		return value.BooleanValue.String()
+	}
+	return value.StringValue.String()
+}
+
 func (r *featureStateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	r.update(ctx, req, resp, false)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The state keeps the planned values of the unmanaged fields, which were
+	// written back as they are in Flagsmith
+	var plan, data FeatureStateResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.CopyUnmanagedFields(&plan)
+
+	diags := resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// update writes the plan to Flagsmith. Unless writeUnmanaged is set, the fields
+// left out of `managed_fields` are written with their current values.
+func (r *featureStateResource) update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse, writeUnmanaged bool) {
 	// Get plan values
 	var plan FeatureStateResourceData
 	diags := req.Plan.Get(ctx, &plan)
@@ -265,6 +827,16 @@ func (r *featureStateResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
+	if !writeUnmanaged && (!plan.Manages(ManagedFieldEnabled) || !plan.Manages(ManagedFieldValue)) {
+		current, err := r.currentFeatureState(&state)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature state, got error: %s", err))
+			return
+		}
+		currentData := MakeFeatureStateResourceDataFromClientFS(current)
+		plan.CopyUnmanagedFields(&currentData)
+	}
+
 	// Generate API request body from plan
 	clientFeatureState := plan.ToClientFS()
 
@@ -274,15 +846,88 @@ func (r *featureStateResource) Update(ctx context.Context, req resource.UpdateRe
 	intEnvironment := state.Environment.ValueInt64()
 	clientFeatureState.Environment = &intEnvironment
 
+	// Segment overrides are written directly, the plan refuses the ones that
+	// would need an approval
+	isSegmentOverride := state.FeatureSegment.ValueInt64() != 0
+	if !isSegmentOverride {
+		// Supersede the change request or the scheduled change of a previous apply
+		err := r.withdrawChangeRequests(&state)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete superseded change request, got error: %s", err))
+			return
+		}
+
+		useChangeRequest, err := r.client.UseChangeRequest(plan.EnvironmentKey.ValueString(), plan.ChangeRequestMode.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment, got error: %s", err))
+			return
+		}
+		if useChangeRequest || plan.IsScheduled(time.Now()) {
+			r.openChangeRequest(ctx, &plan, &state, useChangeRequest, resp)
+			return
+		}
+	}
+	if isSegmentOverride {
+		unlock := r.client.LockSegmentOverrides(state.EnvironmentKey.ValueString(), state.Feature.ValueInt64())
+		defer unlock()
+	}
+
+	versioned, err := r.client.IsV2Versioned(state.EnvironmentKey.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment versioning, got error: %s", err))
+		return
+	}
+	if versioned {
+		if isSegmentOverride {
+			segment := state.Segment.ValueInt64()
+			clientFeatureState.Segment = &segment
+		}
+		version := FeatureVersion{
+			FeatureStatesToUpdate: []FeatureVersionFeatureState{NewFeatureVersionFeatureState(clientFeatureState)},
+		}
+		featureState, err := r.publishFeatureState(state.EnvironmentKey.ValueString(), state.Feature.ValueInt64(), clientFeatureState.Segment, &version)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to publish feature state, got error: %s", err))
+			return
+		}
+		resourceData := MakeFeatureStateResourceDataFromClientFS(featureState)
+		resourceData.EnvironmentKey = plan.EnvironmentKey
+		resourceData.ChangeRequestMode = plan.ChangeRequestMode
+		resourceData.LiveFrom = plan.LiveFrom
+		resourceData.ManagedFields = plan.ManagedFields
+		if plan.FeatureStateValue.IsSensitive() {
+			resourceData.FeatureStateValue.MarkSensitive()
+		}
+		resourceData.FeatureVersion = types.StringValue(version.UUID)
+
+		diags = resp.State.Set(ctx, &resourceData)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
 	updateSegmentPriority := state.SegmentPriority.ValueInt64() != plan.SegmentPriority.ValueInt64()
-	err := r.client.UpdateFeatureState(clientFeatureState, updateSegmentPriority)
+	err = r.client.UpdateFeatureState(clientFeatureState, updateSegmentPriority)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update feature state, got error: %s", err))
 		return
 	}
+	if isSegmentOverride {
+		// Read the override back since updating the priority can reorder the other overrides
+		clientFeatureState, err = r.client.GetFeatureState(state.UUID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read segment override after update, got error: %s", err))
+			return
+		}
+	}
 	resourceData := MakeFeatureStateResourceDataFromClientFS(clientFeatureState)
 	resourceData.EnvironmentKey = plan.EnvironmentKey
+	resourceData.ChangeRequestMode = plan.ChangeRequestMode
+	resourceData.LiveFrom = plan.LiveFrom
+	resourceData.ManagedFields = plan.ManagedFields
+	if plan.FeatureStateValue.IsSensitive() {
+		resourceData.FeatureStateValue.MarkSensitive()
+	}
 
 	// Update the state with the new values
 	diags = resp.State.Set(ctx, &resourceData)
@@ -300,9 +945,29 @@ func (r *featureStateResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
+	err := r.withdrawChangeRequests(&state)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete change request, got error: %s", err))
+		return
+	}
+
 	// Delete feature segment if it exists
 	if state.FeatureSegment.ValueInt64() != 0 {
-		err := r.client.DeleteFeatureSegment(state.FeatureSegment.ValueInt64())
+		unlock := r.client.LockSegmentOverrides(state.EnvironmentKey.ValueString(), state.Feature.ValueInt64())
+		defer unlock()
+
+		versioned, err := r.client.IsV2Versioned(state.EnvironmentKey.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment versioning, got error: %s", err))
+			return
+		}
+		if versioned {
+			err = r.client.PublishFeatureVersion(state.Environment.ValueInt64(), state.Feature.ValueInt64(), &FeatureVersion{
+				SegmentIDsToDeleteOverrides: []int64{state.Segment.ValueInt64()},
+			})
+		} else {
+			err = r.client.DeleteFeatureSegment(state.FeatureSegment.ValueInt64())
+		}
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete feature segment, got error: %s", err))
 			return
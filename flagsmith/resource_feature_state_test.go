@@ -20,7 +20,7 @@ func TestAccEnvironmentFeatureStateResource(t *testing.T) {
 			// Test feature State value validator
 			{
 				Config:      testAccInvalidFeatureStateValueConfig(),
-				ExpectError: regexp.MustCompile(`Exactly one of these attributes must be configured:\n\[feature_state_value.string_value,feature_state_value.integer_value,feature_state_value.boolean_value\]`),
+				ExpectError: regexp.MustCompile(`Exactly one of these attributes must be configured:\n\[feature_state_value.string_value,feature_state_value.sensitive_string_value,feature_state_value.integer_value,feature_state_value.boolean_value\]`),
 			},
 			// Test feature State string value validator
 			{
@@ -70,6 +70,27 @@ func TestAccEnvironmentFeatureStateResource(t *testing.T) {
 					resource.TestCheckResourceAttr("flagsmith_feature_state.dummy_environment_feature_x", "enabled", "false"),
 				),
 			},
+
+			// Only enabled is written once the value is left out of managed_fields,
+			// which is then read from Flagsmith
+			{
+				Config: testAccEnvironmentFeatureStateManagedFieldsConfig(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature_state.dummy_environment_feature_x", "feature_state_value.string_value", "two"),
+					testAccCheckEnvironmentFeatureStateValue("two", true),
+					resource.TestCheckResourceAttr("flagsmith_feature_state.dummy_environment_feature_x", "enabled", "true"),
+					resource.TestCheckResourceAttr("flagsmith_feature_state.dummy_environment_feature_x", "managed_fields.#", "1"),
+				),
+			},
+
+			// Sensitive value testing
+			{
+				Config: testAccEnvironmentFeatureStateSensitiveConfig("four"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature_state.dummy_environment_feature_x", "feature_state_value.sensitive_string_value", "four"),
+					resource.TestCheckNoResourceAttr("flagsmith_feature_state.dummy_environment_feature_x", "feature_state_value.string_value"),
+				),
+			},
 		},
 	})
 }
@@ -224,6 +245,59 @@ resource "flagsmith_feature_state" "dummy_environment_feature_x" {
 
 `, isEnabled, environmentKey(), featureID(), featureStateValue)
 }
+
+// testAccCheckEnvironmentFeatureStateValue checks the string value and enabled
+// of the feature state in Flagsmith
+func testAccCheckEnvironmentFeatureStateValue(stringValue string, enabled bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		featureState, err := testClient().GetEnvironmentFeatureState(environmentKey(), int64(featureID()))
+		if err != nil {
+			return err
+		}
+		if featureState.Enabled != enabled {
+			return fmt.Errorf("expected enabled to be %t, got %t", enabled, featureState.Enabled)
+		}
+		if featureState.FeatureStateValue == nil || featureState.FeatureStateValue.StringValue == nil || *featureState.FeatureStateValue.StringValue != stringValue {
+			return fmt.Errorf("expected the feature state value to be %q, got %+v", stringValue, featureState.FeatureStateValue)
+		}
+		return nil
+	}
+}
+
+func testAccEnvironmentFeatureStateManagedFieldsConfig(isEnabled bool) string {
+	return fmt.Sprintf(`
+provider "flagsmith" {
+
+}
+
+resource "flagsmith_feature_state" "dummy_environment_feature_x" {
+  enabled         = %t
+  environment_key = "%s"
+  feature_id = %d
+  managed_fields = ["enabled"]
+}
+
+`, isEnabled, environmentKey(), featureID())
+}
+
+func testAccEnvironmentFeatureStateSensitiveConfig(featureStateValue string) string {
+	return fmt.Sprintf(`
+provider "flagsmith" {
+
+}
+
+resource "flagsmith_feature_state" "dummy_environment_feature_x" {
+  enabled         = true
+  environment_key = "%s"
+  feature_id = %d
+  feature_state_value = {
+    type                   = "unicode"
+    sensitive_string_value = "%s"
+  }
+}
+
+`, environmentKey(), featureID(), featureStateValue)
+}
 func testAccInvalidFeatureStateValueConfig() string {
 	return fmt.Sprintf(`
 provider "flagsmith" {
@@ -0,0 +1,98 @@
+package flagsmith_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccFeatureTagAndOwnerResources(t *testing.T) {
+	featureName := acctest.RandString(16)
+	ownTagName := acctest.RandString(16)
+	attachedTagName := acctest.RandString(16)
+	ownerUserID := 3936
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckFeatureResourceDestroy,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccFeatureTagAndOwnerResourcesConfig(featureName, ownTagName, attachedTagName, ownerUserID, "feature with attached tags"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "tags_authoritative", "false"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "owners_authoritative", "false"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "tag_names.#", "1"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "tag_names.0", ownTagName),
+					resource.TestCheckNoResourceAttr("flagsmith_feature.test_feature", "owners"),
+
+					resource.TestCheckResourceAttrPair("flagsmith_feature_tag.test_feature_tag", "feature_uuid", "flagsmith_feature.test_feature", "uuid"),
+					resource.TestCheckResourceAttrPair("flagsmith_feature_tag.test_feature_tag", "tag_id", "flagsmith_tag.attached_tag", "id"),
+					resource.TestCheckResourceAttrSet("flagsmith_feature_tag.test_feature_tag", "id"),
+
+					resource.TestCheckResourceAttr("flagsmith_feature_owner.test_feature_owner", "user_id", fmt.Sprintf("%d", ownerUserID)),
+					resource.TestCheckResourceAttrSet("flagsmith_feature_owner.test_feature_owner", "id"),
+				),
+			},
+
+			// ImportState testing
+			{
+				ResourceName:      "flagsmith_feature_tag.test_feature_tag",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "flagsmith_feature_owner.test_feature_owner",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+
+			// Updating the feature leaves the attached tag and owner untouched
+			{
+				Config: testAccFeatureTagAndOwnerResourcesConfig(featureName, ownTagName, attachedTagName, ownerUserID, "updated feature with attached tags"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "description", "updated feature with attached tags"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "tag_names.#", "1"),
+					resource.TestCheckResourceAttrSet("flagsmith_feature_tag.test_feature_tag", "id"),
+					resource.TestCheckResourceAttrSet("flagsmith_feature_owner.test_feature_owner", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccFeatureTagAndOwnerResourcesConfig(featureName, ownTagName, attachedTagName string, ownerUserID int, description string) string {
+	return providerConfig() + fmt.Sprintf(`
+resource "flagsmith_tag" "own_tag" {
+  tag_name     = "%s"
+  project_uuid = "%s"
+}
+
+resource "flagsmith_tag" "attached_tag" {
+  tag_name     = "%s"
+  project_uuid = "%s"
+}
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name         = "%s"
+  project_uuid         = "%s"
+  description          = "%s"
+  tag_names            = [flagsmith_tag.own_tag.tag_name]
+  tags_authoritative   = false
+  owners_authoritative = false
+}
+
+resource "flagsmith_feature_tag" "test_feature_tag" {
+  feature_uuid = flagsmith_feature.test_feature.uuid
+  tag_id       = flagsmith_tag.attached_tag.id
+}
+
+resource "flagsmith_feature_owner" "test_feature_owner" {
+  feature_uuid = flagsmith_feature.test_feature.uuid
+  user_id      = %d
+}
+`, ownTagName, projectUUID(), attachedTagName, projectUUID(), featureName, projectUUID(), description, ownerUserID)
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -239,3 +241,435 @@ resource "flagsmith_feature" "test_feature" {
 
 `, projectUUID(), featureName, description, projectUUID(), strings.Join(strings.Fields(fmt.Sprint(owners)), ","))
 }
+
+func TestAccFeatureResourceDeletionMode(t *testing.T) {
+	featureName := acctest.RandString(16)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckFeatureResourceArchived,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFeatureResourceWithDeletionModeConfig(featureName, "prevent"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "deletion_mode", "prevent"),
+				),
+			},
+			// Removing the resource fails while deletion_mode is prevent
+			{
+				Config:      providerConfig(),
+				ExpectError: regexp.MustCompile("Feature Deletion Prevented"),
+			},
+			{
+				Config: testAccFeatureResourceWithDeletionModeConfig(featureName, "archive"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "deletion_mode", "archive"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "is_archived", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckFeatureResourceArchived(s *terraform.State) error {
+	uuid, err := getAttributefromState(s, "flagsmith_feature.test_feature", "uuid")
+	if err != nil {
+		return err
+	}
+
+	feature, err := testClient().GetFeature(uuid)
+	if err != nil {
+		return fmt.Errorf("feature was deleted instead of archived: %s", err)
+	}
+	if !feature.IsArchived {
+		return fmt.Errorf("feature was not archived")
+	}
+	return testClient().DeleteFeature(*feature.ProjectID, *feature.ID)
+}
+
+func testAccFeatureResourceWithDeletionModeConfig(featureName, deletionMode string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name  = "%s"
+  project_uuid  = "%s"
+  type          = "STANDARD"
+  deletion_mode = "%s"
+}
+`, providerConfig(), featureName, projectUUID(), deletionMode)
+}
+
+func TestAccFeatureResourceNameRules(t *testing.T) {
+	projectName := acctest.RandString(16)
+	featureName := acctest.RandString(16)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Features of a project created in the same plan are validated against its planned rules
+			{
+				Config:      testAccFeatureResourceWithNameRulesConfig(projectName, "Invalid_"+featureName),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`only allows lower case feature names`),
+			},
+			{
+				Config:      testAccFeatureResourceWithNameRulesConfig(projectName, featureName+"-v1"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must match the regex`),
+			},
+			// Features of existing projects are validated during the plan
+			{
+				Config:      testAccFeatureResourceWithNameRulesConfig(projectName, featureName+"-v2"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must match the regex`),
+			},
+			{
+				Config: testAccFeatureResourceWithNameRulesConfig(projectName, featureName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "feature_name", featureName),
+				),
+			},
+		},
+	})
+}
+
+func testAccFeatureResourceWithNameRulesConfig(projectName, featureName string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flagsmith_project" "test_project" {
+  name                                = "%s"
+  organisation_id                     = %d
+  only_allow_lower_case_feature_names = true
+  feature_name_regex                  = "[a-z_]+"
+}
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name = "%s"
+  project_uuid = flagsmith_project.test_project.uuid
+  type         = "STANDARD"
+}
+`, providerConfig(), projectName, organisationID(), featureName)
+}
+
+func TestAccFeatureResourceEnforceOwners(t *testing.T) {
+	projectName := acctest.RandString(16)
+	featureName := acctest.RandString(16)
+	ownerID := 3936
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccFeatureResourceWithEnforcedOwnersConfig(projectName, featureName, []int{}),
+				ExpectError: regexp.MustCompile("Feature Owners Required"),
+			},
+			{
+				Config: testAccFeatureResourceWithEnforcedOwnersConfig(projectName, featureName, []int{ownerID}),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "owners.0", fmt.Sprintf("%d", ownerID)),
+				),
+			},
+			// Removing the last owner is refused during the plan
+			{
+				Config:      testAccFeatureResourceWithEnforcedOwnersConfig(projectName, featureName, []int{}),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Removing the last owner.*enforce_feature_owners`),
+			},
+		},
+	})
+}
+
+func testAccFeatureResourceWithEnforcedOwnersConfig(projectName, featureName string, owners []int) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flagsmith_project" "test_project" {
+  name                   = "%s"
+  organisation_id        = %d
+  enforce_feature_owners = true
+}
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name = "%s"
+  project_uuid = flagsmith_project.test_project.uuid
+  type         = "STANDARD"
+  owners       = %s
+}
+`, providerConfig(), projectName, organisationID(), featureName, strings.Join(strings.Fields(fmt.Sprint(owners)), ","))
+}
+
+func TestAccFeatureResourceOwnerEmails(t *testing.T) {
+	featureName := acctest.RandString(16)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckFeatureResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccFeatureResourceWithOwnerEmailsConfig(featureName, "unknown-"+userEmail()),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Unknown Feature Owner"),
+			},
+			{
+				Config: testAccFeatureResourceWithOwnerEmailsConfig(featureName, userEmail()),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "owner_emails.#", "1"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "owner_emails.0", userEmail()),
+					resource.TestCheckNoResourceAttr("flagsmith_feature.test_feature", "owners"),
+				),
+			},
+		},
+	})
+}
+
+func testAccFeatureResourceWithOwnerEmailsConfig(featureName, email string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name = "%s"
+  project_uuid = "%s"
+  type         = "STANDARD"
+  owner_emails = ["%s"]
+}
+`, providerConfig(), featureName, projectUUID(), email)
+}
+
+func TestAccFeatureResourceTagNames(t *testing.T) {
+	featureName := acctest.RandString(16)
+	tagName := acctest.RandString(16)
+	renamedTagName := acctest.RandString(16)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckFeatureResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFeatureResourceWithTagNamesConfig(featureName, tagName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "tag_names.#", "1"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "tag_names.0", tagName),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "create_missing_tags", "false"),
+					resource.TestCheckNoResourceAttr("flagsmith_feature.test_feature", "tags"),
+				),
+			},
+			// Renaming the tag is read back as a change of tag_names
+			{
+				Config: testAccFeatureResourceWithTagNamesConfig(featureName, renamedTagName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "tag_names.#", "1"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "tag_names.0", renamedTagName),
+				),
+			},
+		},
+	})
+}
+
+func testAccFeatureResourceWithTagNamesConfig(featureName, tagName string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flagsmith_tag" "test_tag" {
+  tag_name     = "%s"
+  tag_colour   = "#000000"
+  project_uuid = "%s"
+}
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name = "%s"
+  project_uuid = "%s"
+  type         = "STANDARD"
+  tag_names    = [flagsmith_tag.test_tag.tag_name]
+}
+`, providerConfig(), tagName, projectUUID(), featureName, projectUUID())
+}
+
+func TestAccFeatureResourceEnvironmentStates(t *testing.T) {
+	featureName := acctest.RandString(16)
+	environmentName := acctest.RandString(16)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckFeatureResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFeatureResourceWithEnvironmentStatesConfig(environmentName, featureName, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "include_environment_states", "false"),
+					resource.TestCheckNoResourceAttr("flagsmith_feature.test_feature", "environment_states"),
+				),
+			},
+			{
+				Config: testAccFeatureResourceWithEnvironmentStatesConfig(environmentName, featureName, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "include_environment_states", "true"),
+					resource.TestCheckResourceAttrSet("flagsmith_feature.test_feature", "environment_states.%"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", fmt.Sprintf("environment_states.%s.enabled", environmentName), "true"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", fmt.Sprintf("environment_states.%s.feature_state_value.type", environmentName), "int"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", fmt.Sprintf("environment_states.%s.feature_state_value.integer_value", environmentName), "7"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", fmt.Sprintf("environment_states.%s.segment_override_count", environmentName), "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccFeatureResourceWithEnvironmentStatesConfig(environmentName, featureName string, include bool) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flagsmith_environment" "test_environment" {
+  name       = "%s"
+  project_id = %d
+}
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name               = "%s"
+  project_uuid               = "%s"
+  type                       = "STANDARD"
+  default_enabled            = true
+  initial_value              = "7"
+  include_environment_states = %t
+
+  depends_on = [flagsmith_environment.test_environment]
+}
+`, providerConfig(), environmentName, projectID(), featureName, projectUUID(), include)
+}
+
+func TestAccFeatureResourceEnvironmentValues(t *testing.T) {
+	featureName := acctest.RandString(16)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckFeatureResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFeatureResourceWithEnvironmentValuesConfig(featureName, 10, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", fmt.Sprintf("environment_values.%s.enabled", environmentKey()), "true"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", fmt.Sprintf("environment_values.%s.feature_state_value.integer_value", environmentKey()), "10"),
+				),
+			},
+			{
+				Config: testAccFeatureResourceWithEnvironmentValuesConfig(featureName, 20, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", fmt.Sprintf("environment_values.%s.feature_state_value.integer_value", environmentKey()), "20"),
+				),
+			},
+			// A flagsmith_feature_state can not manage the same feature state
+			{
+				Config: testAccFeatureResourceWithEnvironmentValuesConfig(featureName, 20, `
+resource "flagsmith_feature_state" "test_feature_state" {
+  enabled         = false
+  environment_key = "`+environmentKey()+`"
+  feature_id      = flagsmith_feature.test_feature.id
+  feature_state_value = {
+    type          = "int"
+    integer_value = 30
+  }
+}
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Feature State Managed Twice"),
+			},
+		},
+	})
+}
+
+func testAccFeatureResourceWithEnvironmentValuesConfig(featureName string, value int, extra string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name = "%s"
+  project_uuid = "%s"
+  type         = "STANDARD"
+  environment_values = {
+    "%s" = {
+      enabled = true
+      feature_state_value = {
+        type          = "int"
+        integer_value = %d
+      }
+    }
+  }
+}
+%s
+`, providerConfig(), featureName, projectUUID(), environmentKey(), value, extra)
+}
+
+func TestAccFeatureResourceInitialFeatureStateValue(t *testing.T) {
+	featureName := acctest.RandString(16)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckFeatureResourceDestroy,
+		Steps: []resource.TestStep{
+			// A numeric string stays a string, where initial_value infers an int
+			{
+				Config: testAccFeatureResourceWithInitialFeatureStateValueConfig(featureName, `{
+    type         = "unicode"
+    string_value = "10"
+  }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "initial_value", "10"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "initial_feature_state_value.type", "unicode"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", "initial_feature_state_value.string_value", "10"),
+					testAccCheckFeatureEnvironmentValue("unicode"),
+				),
+			},
+			{
+				Config: testAccFeatureResourceWithInitialFeatureStateValueConfig(featureName, `{
+    type          = "unicode"
+    string_value  = "10"
+    integer_value = 10
+  }`),
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+		},
+	})
+}
+
+func testAccCheckFeatureEnvironmentValue(expectedType string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		featureID, err := getAttributefromState(s, "flagsmith_feature.test_feature", "id")
+		if err != nil {
+			return err
+		}
+		id, err := strconv.ParseInt(featureID, 10, 64)
+		if err != nil {
+			return err
+		}
+		featureState, err := testClient().GetEnvironmentFeatureState(environmentKey(), id)
+		if err != nil {
+			return err
+		}
+		if featureState.FeatureStateValue == nil || featureState.FeatureStateValue.Type != expectedType {
+			return fmt.Errorf("expected a feature state value of type %q, got %+v", expectedType, featureState.FeatureStateValue)
+		}
+		return nil
+	}
+}
+
+func testAccFeatureResourceWithInitialFeatureStateValueConfig(featureName, value string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name = "%s"
+  project_uuid = "%s"
+  type         = "STANDARD"
+  initial_feature_state_value = %s
+}
+`, providerConfig(), featureName, projectUUID(), value)
+}
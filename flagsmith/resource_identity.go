@@ -0,0 +1,402 @@
+package flagsmith
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &identityResource{}
+var _ resource.ResourceWithImportState = &identityResource{}
+var _ resource.ResourceWithValidateConfig = &identityResource{}
+
+// traitValueAttrTypes are the attribute types of a trait value
+var traitValueAttrTypes = map[string]attr.Type{
+	"string_value":  types.StringType,
+	"integer_value": types.Int64Type,
+	"float_value":   types.Float64Type,
+	"boolean_value": types.BoolType,
+}
+
+func newIdentityResource() resource.Resource {
+	return &identityResource{}
+}
+
+type identityResource struct {
+	client *fsClient
+}
+
+func (r *identityResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_identity"
+}
+
+func (r *identityResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*fsClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (t *identityResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Flagsmith Identity of an environment along with its traits",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the identity, its UUID for Edge identities",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"environment_key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Client side environment key associated with the environment",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"identifier": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Identifier of the identity",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"edge_identities": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Use the Edge identities API. Defaults to whether the project of the environment stores its identities in Edge(e.g: on Flagsmith SaaS)",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"traits": schema.MapNestedAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Traits of the identity keyed by trait key. If set, the traits that are not listed are deleted, otherwise the traits are left untouched. NOTE: One of string_value, integer_value, float_value or boolean_value must be set",
+				PlanModifiers:       []planmodifier.Map{mapplanmodifier.UseStateForUnknown()},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"string_value": schema.StringAttribute{
+							MarkdownDescription: "String value of the trait",
+							Optional:            true,
+						},
+						"integer_value": schema.Int64Attribute{
+							MarkdownDescription: "Integer value of the trait",
+							Optional:            true,
+						},
+						"float_value": schema.Float64Attribute{
+							MarkdownDescription: "Float value of the trait",
+							Optional:            true,
+						},
+						"boolean_value": schema.BoolAttribute{
+							MarkdownDescription: "Boolean value of the trait",
+							Optional:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *identityResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var traits types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("traits"), &traits)...)
+	if resp.Diagnostics.HasError() || traits.IsNull() || traits.IsUnknown() {
+		return
+	}
+	var data map[string]TraitValue
+	resp.Diagnostics.Append(traits.ElementsAs(ctx, &data, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for key, trait := range data {
+		set := 0
+		for _, isNull := range []bool{trait.StringValue.IsNull(), trait.IntegerValue.IsNull(), trait.FloatValue.IsNull(), trait.BooleanValue.IsNull()} {
+			if !isNull {
+				set++
+			}
+		}
+		if set != 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("traits").AtMapKey(key),
+				"Invalid Attribute Combination",
+				"Exactly one of string_value, integer_value, float_value or boolean_value must be set",
+			)
+		}
+	}
+}
+
+// edgeIdentities returns whether the Edge identities API is used by the
+// identity, which defaults to whether the project stores its identities in Edge
+func (r *identityResource) edgeIdentities(data *IdentityResourceData) (bool, error) {
+	if !data.EdgeIdentities.IsNull() && !data.EdgeIdentities.IsUnknown() {
+		return data.EdgeIdentities.ValueBool(), nil
+	}
+	return r.client.UsesEdgeIdentities(data.EnvironmentKey.ValueString())
+}
+
+// readTraits sets the traits of the identity on data, prior being the traits
+// they replace
+func (r *identityResource) readTraits(ctx context.Context, data *IdentityResourceData, prior map[string]TraitValue) diag.Diagnostics {
+	var diags diag.Diagnostics
+	traits, err := r.client.GetIdentityTraits(data.EnvironmentKey.ValueString(), data.ID.ValueString(), data.EdgeIdentities.ValueBool())
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read traits, got error: %s", err))
+		return diags
+	}
+	traitValues := map[string]TraitValue{}
+	for key, trait := range traits {
+		var priorValue *TraitValue
+		if value, ok := prior[key]; ok {
+			priorValue = &value
+		}
+		traitValues[key] = MakeTraitValueFromClientTrait(&trait, priorValue)
+	}
+	data.Traits, diags = types.MapValueFrom(ctx, types.ObjectType{AttrTypes: traitValueAttrTypes}, traitValues)
+	return diags
+}
+
+// applyTraits sets the desired traits of the identity, and deletes the ones
+// that are not desired anymore
+func (r *identityResource) applyTraits(ctx context.Context, data *IdentityResourceData, desired map[string]TraitValue) error {
+	environmentKey, identityID, edge := data.EnvironmentKey.ValueString(), data.ID.ValueString(), data.EdgeIdentities.ValueBool()
+	current, err := r.client.GetIdentityTraits(environmentKey, identityID, edge)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(current))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		trait := current[key]
+		tflog.Debug(ctx, "Deleting trait", map[string]interface{}{"trait_key": key})
+		err = r.client.DeleteIdentityTrait(environmentKey, identityID, edge, &trait)
+		if err != nil {
+			return err
+		}
+	}
+
+	keys = keys[:0]
+	for key := range desired {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := desired[key]
+		existing, ok := current[key]
+		if ok && value.Equal(MakeTraitValueFromClientTrait(&existing, &value)) {
+			continue
+		}
+		trait := value.ToClientTrait(key)
+		trait.ID = existing.ID
+		err = r.client.SetIdentityTrait(environmentKey, identityID, edge, trait)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *identityResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data IdentityResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	environmentKey, identifier := data.EnvironmentKey.ValueString(), data.Identifier.ValueString()
+
+	edge, err := r.edgeIdentities(&data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read project, got error: %s", err))
+		return
+	}
+	data.EdgeIdentities = types.BoolValue(edge)
+
+	// Deleting the resource deletes the identity, so an existing one is not taken over
+	_, err = r.client.GetIdentityID(environmentKey, identifier, edge)
+	if err == nil {
+		resp.Diagnostics.AddError("Identity Already Exists", fmt.Sprintf("Identity %q already exists in environment %q, import it to manage it", identifier, environmentKey))
+		return
+	}
+	if _, ok := err.(IdentityNotFoundError); !ok {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read identities, got error: %s", err))
+		return
+	}
+
+	identityID, err := r.client.CreateIdentityByIdentifier(environmentKey, identifier, edge)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create identity, got error: %s", err))
+		return
+	}
+	data.ID = types.StringValue(identityID)
+
+	var desired map[string]TraitValue
+	if !data.Traits.IsUnknown() {
+		resp.Diagnostics.Append(data.Traits.ElementsAs(ctx, &desired, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		err = r.applyTraits(ctx, &data, desired)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to set traits, got error: %s", err))
+			return
+		}
+	}
+	resp.Diagnostics.Append(r.readTraits(ctx, &data, desired)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *identityResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data IdentityResourceData
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	// Early return if the state is wrong
+	if diags.HasError() {
+		return
+	}
+
+	edge, err := r.edgeIdentities(&data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read project, got error: %s", err))
+		return
+	}
+	data.EdgeIdentities = types.BoolValue(edge)
+
+	identityID, err := r.client.GetIdentityID(data.EnvironmentKey.ValueString(), data.Identifier.ValueString(), edge)
+	if err != nil {
+		if _, ok := err.(IdentityNotFoundError); ok {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read identity, got error: %s", err))
+		return
+	}
+	data.ID = types.StringValue(identityID)
+
+	var prior map[string]TraitValue
+	if !data.Traits.IsNull() && !data.Traits.IsUnknown() {
+		resp.Diagnostics.Append(data.Traits.ElementsAs(ctx, &prior, false)...)
+	}
+	resp.Diagnostics.Append(r.readTraits(ctx, &data, prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *identityResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Get plan values
+	var plan IdentityResourceData
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Update: Error reading plan data")
+		return
+	}
+
+	// Get current state
+	var state IdentityResourceData
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Update: Error reading state data")
+		return
+	}
+	plan.ID = state.ID
+	plan.EdgeIdentities = state.EdgeIdentities
+
+	var desired map[string]TraitValue
+	resp.Diagnostics.Append(plan.Traits.ElementsAs(ctx, &desired, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err := r.applyTraits(ctx, &plan, desired)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to set traits, got error: %s", err))
+		return
+	}
+	resp.Diagnostics.Append(r.readTraits(ctx, &plan, desired)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update the state with the new values
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *identityResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Get current state
+	var state IdentityResourceData
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Delete: Error reading state data")
+		return
+	}
+
+	err := r.client.DeleteIdentityByID(state.EnvironmentKey.ValueString(), state.ID.ValueString(), state.EdgeIdentities.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete identity, got error: %s", err))
+		return
+	}
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *identityResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The identifier may itself contain slashes
+	environmentKey, identifier, found := strings.Cut(req.ID, "/")
+	if !found || environmentKey == "" || identifier == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: environment_key/identifier Got: %q", req.ID),
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("environment_key"), environmentKey)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("identifier"), identifier)...)
+}
@@ -0,0 +1,332 @@
+package flagsmith
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &identityOverrideResource{}
+var _ resource.ResourceWithImportState = &identityOverrideResource{}
+var _ resource.ResourceWithConfigValidators = &identityOverrideResource{}
+var _ resource.ResourceWithModifyPlan = &identityOverrideResource{}
+
+func newIdentityOverrideResource() resource.Resource {
+	return &identityOverrideResource{}
+}
+
+type identityOverrideResource struct {
+	client *fsClient
+}
+
+func (r *identityOverrideResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_identity_override"
+}
+
+func (r *identityOverrideResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*fsClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (t *identityOverrideResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Flagsmith Identity override of a feature in an environment. The identity is created if it does not exist yet, and is kept when the override is destroyed",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the featurestate of the identity override, its UUID for Edge identities",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"environment_key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Client side environment key associated with the environment",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"identifier": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Identifier of the identity",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"identity_id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the identity, its UUID for Edge identities",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"feature_id": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "ID of the feature",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+			},
+			"feature_state_value": featureStateValueSchema(),
+
+			"enabled": schema.BoolAttribute{
+				Required:            true,
+				MarkdownDescription: "Used for enabling/disabling the feature for the identity",
+			},
+			"edge_identities": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Use the Edge identities API. Defaults to whether the project of the environment stores its identities in Edge(e.g: on Flagsmith SaaS)",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *identityOverrideResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("feature_state_value").AtName("string_value"),
+			path.MatchRoot("feature_state_value").AtName("sensitive_string_value"),
+			path.MatchRoot("feature_state_value").AtName("integer_value"),
+			path.MatchRoot("feature_state_value").AtName("boolean_value"),
+		),
+	}
+}
+
+func (r *identityOverrideResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is planned on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan IdentityOverrideResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateSensitiveFeatureStateValue(r.client, plan.EnvironmentKey, plan.Feature, plan.FeatureStateValue)...)
+}
+
+// edgeIdentities returns whether the Edge identities API is used by the
+// override, which defaults to whether the project stores its identities in Edge
+func (r *identityOverrideResource) edgeIdentities(data *IdentityOverrideResourceData) (bool, error) {
+	if !data.EdgeIdentities.IsNull() && !data.EdgeIdentities.IsUnknown() {
+		return data.EdgeIdentities.ValueBool(), nil
+	}
+	return r.client.UsesEdgeIdentities(data.EnvironmentKey.ValueString())
+}
+
+func (r *identityOverrideResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data IdentityOverrideResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	environmentKey := data.EnvironmentKey.ValueString()
+
+	edge, err := r.edgeIdentities(&data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read project, got error: %s", err))
+		return
+	}
+
+	identityID, err := r.client.GetIdentityID(environmentKey, data.Identifier.ValueString(), edge)
+	if _, ok := err.(IdentityNotFoundError); ok {
+		tflog.Info(ctx, "Creating identity", map[string]interface{}{"identifier": data.Identifier.ValueString()})
+		identityID, err = r.client.CreateIdentityByIdentifier(environmentKey, data.Identifier.ValueString(), edge)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get identity, got error: %s", err))
+		return
+	}
+
+	override := data.ToIdentityOverride()
+	// Take over the override if the identity already has one for the feature
+	existing, err := r.client.GetIdentityOverride(environmentKey, identityID, data.Feature.ValueInt64(), edge)
+	switch err.(type) {
+	case nil:
+		override.ID = existing.ID
+		err = r.client.UpdateIdentityOverride(environmentKey, identityID, edge, override)
+	case IdentityOverrideNotFoundError:
+		err = r.client.CreateIdentityOverride(environmentKey, identityID, edge, override)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create identity override, got error: %s", err))
+		return
+	}
+
+	data.ID = types.StringValue(override.ID)
+	data.Identity = types.StringValue(identityID)
+	data.EdgeIdentities = types.BoolValue(edge)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *identityOverrideResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data IdentityOverrideResourceData
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	// Early return if the state is wrong
+	if diags.HasError() {
+		return
+	}
+	environmentKey := data.EnvironmentKey.ValueString()
+
+	edge, err := r.edgeIdentities(&data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read project, got error: %s", err))
+		return
+	}
+
+	// The identity is looked up by identifier in case it has been recreated
+	identityID, err := r.client.GetIdentityID(environmentKey, data.Identifier.ValueString(), edge)
+	if err == nil {
+		var override *IdentityOverride
+		override, err = r.client.GetIdentityOverride(environmentKey, identityID, data.Feature.ValueInt64(), edge)
+		if err == nil {
+			resourceData := MakeIdentityOverrideResourceData(override)
+			resourceData.EnvironmentKey = data.EnvironmentKey
+			resourceData.Identifier = data.Identifier
+			resourceData.Identity = types.StringValue(identityID)
+			resourceData.EdgeIdentities = types.BoolValue(edge)
+
+			// A value set with sensitive_string_value stays sensitive, and so do
+			// the values of the features tagged as such
+			sensitive := data.FeatureStateValue.IsSensitive()
+			if !sensitive {
+				sensitive, err = r.client.IsSensitiveEnvironmentFeature(environmentKey, data.Feature.ValueInt64())
+				if err != nil {
+					resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read the tags of the feature, got error: %s", err))
+					return
+				}
+			}
+			if sensitive {
+				resourceData.FeatureStateValue.MarkSensitive()
+			}
+
+			diags = resp.State.Set(ctx, &resourceData)
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+	switch err.(type) {
+	case IdentityNotFoundError, IdentityOverrideNotFoundError:
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read identity override, got error: %s", err))
+}
+
+func (r *identityOverrideResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Get plan values
+	var plan IdentityOverrideResourceData
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Update: Error reading plan data")
+		return
+	}
+
+	// Get current state
+	var state IdentityOverrideResourceData
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Update: Error reading state data")
+		return
+	}
+
+	// Load computed data from the state
+	plan.ID = state.ID
+	plan.Identity = state.Identity
+	plan.EdgeIdentities = state.EdgeIdentities
+
+	err := r.client.UpdateIdentityOverride(plan.EnvironmentKey.ValueString(), plan.Identity.ValueString(), plan.EdgeIdentities.ValueBool(), plan.ToIdentityOverride())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update identity override, got error: %s", err))
+		return
+	}
+
+	// Update the state with the new values
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *identityOverrideResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Get current state
+	var state IdentityOverrideResourceData
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Delete: Error reading state data")
+		return
+	}
+
+	err := r.client.DeleteIdentityOverride(state.EnvironmentKey.ValueString(), state.Identity.ValueString(), state.EdgeIdentities.ValueBool(), state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete identity override, got error: %s", err))
+		return
+	}
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *identityOverrideResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The identifier may itself contain slashes
+	first, last := strings.Index(req.ID, "/"), strings.LastIndex(req.ID, "/")
+	if first <= 0 || last == first || last == len(req.ID)-1 {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: environment_key/identifier/feature_name Got: %q", req.ID),
+		)
+		return
+	}
+	environmentKey, identifier, featureName := req.ID[:first], req.ID[first+1:last], req.ID[last+1:]
+
+	environment, err := r.client.GetEnvironment(environmentKey)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment, got error: %s", err))
+		return
+	}
+	features, err := r.client.GetProjectFeatures(environment.ProjectID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read project features, got error: %s", err))
+		return
+	}
+	for _, feature := range features {
+		if feature.Name == featureName && feature.ID != nil {
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("environment_key"), environmentKey)...)
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("identifier"), identifier)...)
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("feature_id"), *feature.ID)...)
+			return
+		}
+	}
+	resp.Diagnostics.AddError("Unexpected Import Identifier", fmt.Sprintf("Feature %q not found in the project of environment %q", featureName, environmentKey))
+}
@@ -0,0 +1,83 @@
+package flagsmith_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccIdentityOverrideResource(t *testing.T) {
+	featureName := acctest.RandString(10)
+	identifier := acctest.RandString(10) + "@example.com"
+	resourceName := "flagsmith_identity_override.test_override"
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccIdentityOverrideResourceConfig(featureName, identifier, "one", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "environment_key", environmentKey()),
+					resource.TestCheckResourceAttr(resourceName, "identifier", identifier),
+					resource.TestCheckResourceAttr(resourceName, "feature_state_value.string_value", "one"),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "true"),
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttrSet(resourceName, "identity_id"),
+					resource.TestCheckResourceAttrSet(resourceName, "edge_identities"),
+					resource.TestCheckResourceAttrPair(resourceName, "feature_id", "flagsmith_feature.test_feature", "id"),
+				),
+			},
+
+			// ImportState testing
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: getIdentityOverrideImportID(featureName, identifier),
+			},
+
+			// Update testing
+			{
+				Config: testAccIdentityOverrideResourceConfig(featureName, identifier, "two", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "feature_state_value.string_value", "two"),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "false"),
+				),
+			},
+		},
+	})
+}
+
+func getIdentityOverrideImportID(featureName, identifier string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		return fmt.Sprintf("%s/%s/%s", environmentKey(), identifier, featureName), nil
+	}
+}
+
+func testAccIdentityOverrideResourceConfig(featureName, identifier, value string, isEnabled bool) string {
+	return fmt.Sprintf(`
+provider "flagsmith" {
+}
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name = "%s"
+  project_uuid = "%s"
+  type         = "STANDARD"
+}
+
+resource "flagsmith_identity_override" "test_override" {
+  environment_key = "%s"
+  identifier      = "%s"
+  feature_id      = flagsmith_feature.test_feature.id
+  enabled         = %t
+  feature_state_value = {
+    type         = "unicode"
+    string_value = "%s"
+  }
+}
+`, featureName, projectUUID(), environmentKey(), identifier, isEnabled, value)
+}
@@ -0,0 +1,80 @@
+package flagsmith_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccIdentityResource(t *testing.T) {
+	identifier := acctest.RandString(10) + "@example.com"
+	resourceName := "flagsmith_identity.test_identity"
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccIdentityResourceConfig(identifier, `
+    name    = { string_value = "john" }
+    age     = { integer_value = 42 }
+    score   = { float_value = 4.5 }
+    premium = { boolean_value = true }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "environment_key", environmentKey()),
+					resource.TestCheckResourceAttr(resourceName, "identifier", identifier),
+					resource.TestCheckResourceAttr(resourceName, "traits.%", "4"),
+					resource.TestCheckResourceAttr(resourceName, "traits.name.string_value", "john"),
+					resource.TestCheckResourceAttr(resourceName, "traits.age.integer_value", "42"),
+					resource.TestCheckResourceAttr(resourceName, "traits.score.float_value", "4.5"),
+					resource.TestCheckResourceAttr(resourceName, "traits.premium.boolean_value", "true"),
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttrSet(resourceName, "edge_identities"),
+				),
+			},
+
+			// ImportState testing
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: getIdentityImportID(identifier),
+			},
+
+			// Update testing
+			{
+				Config: testAccIdentityResourceConfig(identifier, `
+    name  = { string_value = "jane" }
+    score = { float_value = 5 }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "traits.%", "2"),
+					resource.TestCheckResourceAttr(resourceName, "traits.name.string_value", "jane"),
+					resource.TestCheckResourceAttr(resourceName, "traits.score.float_value", "5"),
+				),
+			},
+		},
+	})
+}
+
+func getIdentityImportID(identifier string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		return fmt.Sprintf("%s/%s", environmentKey(), identifier), nil
+	}
+}
+
+func testAccIdentityResourceConfig(identifier, traits string) string {
+	return fmt.Sprintf(`
+provider "flagsmith" {
+}
+
+resource "flagsmith_identity" "test_identity" {
+  environment_key = "%s"
+  identifier      = "%s"
+  traits = {%s
+  }
+}
+`, environmentKey(), identifier, traits)
+}
@@ -0,0 +1,108 @@
+package flagsmith_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccMetadataFieldResource(t *testing.T) {
+	fieldName := acctest.RandString(16)
+	featureName := acctest.RandString(16)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccMetadataFieldResourceConfig(fieldName, "field description", featureName, "42"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_metadata_field.test_field", "name", fieldName),
+					resource.TestCheckResourceAttr("flagsmith_metadata_field.test_field", "type", "int"),
+					resource.TestCheckResourceAttr("flagsmith_metadata_field.test_field", "description", "field description"),
+					resource.TestCheckResourceAttr("flagsmith_metadata_field.test_field", "organisation_id", fmt.Sprint(organisationID())),
+					resource.TestCheckResourceAttrSet("flagsmith_metadata_field.test_field", "id"),
+
+					resource.TestCheckResourceAttr("flagsmith_metadata_model_field.test_model_field", "content_type", "feature"),
+					resource.TestCheckResourceAttr("flagsmith_metadata_model_field.test_model_field", "required_for_project_ids.#", "1"),
+					resource.TestCheckResourceAttr("flagsmith_metadata_model_field.test_model_field", "required_for_project_ids.0", fmt.Sprint(projectID())),
+					resource.TestCheckResourceAttrPair("flagsmith_metadata_model_field.test_model_field", "field_id", "flagsmith_metadata_field.test_field", "id"),
+					resource.TestCheckResourceAttrSet("flagsmith_metadata_model_field.test_model_field", "id"),
+
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", fmt.Sprintf("metadata.%s", fieldName), "42"),
+				),
+			},
+
+			// ImportState testing
+			{
+				ResourceName:      "flagsmith_metadata_field.test_field",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "flagsmith_metadata_model_field.test_model_field",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: getMetadataModelFieldImportID("flagsmith_metadata_model_field.test_model_field"),
+			},
+
+			// Update testing
+			{
+				Config: testAccMetadataFieldResourceConfig(fieldName, "updated field description", featureName, "7"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("flagsmith_metadata_field.test_field", "description", "updated field description"),
+					resource.TestCheckResourceAttr("flagsmith_feature.test_feature", fmt.Sprintf("metadata.%s", fieldName), "7"),
+				),
+			},
+
+			// Values are validated against the type of their field
+			{
+				Config:      testAccMetadataFieldResourceConfig(fieldName, "updated field description", featureName, "seven"),
+				ExpectError: regexp.MustCompile("Invalid Metadata Value"),
+			},
+		},
+	})
+}
+
+func testAccMetadataFieldResourceConfig(fieldName, description, featureName, value string) string {
+	return providerConfig() + fmt.Sprintf(`
+resource "flagsmith_metadata_field" "test_field" {
+  organisation_id = %d
+  name            = "%s"
+  type            = "int"
+  description     = "%s"
+}
+
+resource "flagsmith_metadata_model_field" "test_model_field" {
+  organisation_id          = %d
+  field_id                 = flagsmith_metadata_field.test_field.id
+  content_type             = "feature"
+  required_for_project_ids = [%d]
+}
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name = "%s"
+  project_uuid = "%s"
+  metadata = {
+    (flagsmith_metadata_field.test_field.name) = "%s"
+  }
+  depends_on = [flagsmith_metadata_model_field.test_model_field]
+}
+`, organisationID(), fieldName, description, organisationID(), projectID(), featureName, projectUUID(), value)
+}
+
+func getMetadataModelFieldImportID(n string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		// return a string in the format of organisationID,modelFieldID
+		modelFieldID, err := getAttributefromState(s, n, "id")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d,%s", organisationID(), modelFieldID), nil
+	}
+}
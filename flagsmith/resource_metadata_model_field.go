@@ -0,0 +1,254 @@
+package flagsmith
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &metadataModelFieldResource{}
+var _ resource.ResourceWithImportState = &metadataModelFieldResource{}
+
+func newMetadataModelFieldResource() resource.Resource {
+	return &metadataModelFieldResource{}
+}
+
+type metadataModelFieldResource struct {
+	client *fsClient
+}
+
+func (r *metadataModelFieldResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_metadata_model_field"
+}
+
+func (r *metadataModelFieldResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*fsClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *metadataModelFieldResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Flagsmith Metadata Model Field. Attaches a metadata field to features, segments or environments, optionally requiring it in some projects",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the metadata model field",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+			},
+			"organisation_id": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "ID of the organisation the metadata field belongs to",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+			},
+			"field_id": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "ID of the metadata field",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+			},
+			"content_type": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Model the metadata field is attached to, one of `feature`, `segment` or `environment`",
+				Validators: []validator.String{
+					stringvalidator.OneOf(metadataModels...),
+				},
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"required_for_project_ids": schema.SetAttribute{
+				Optional:            true,
+				ElementType:         types.Int64Type,
+				MarkdownDescription: "IDs of the projects whose features, segments or environments must set the metadata field",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+// contentTypes returns the IDs of the content types of the model and of
+// projects
+func (r *metadataModelFieldResource) contentTypes(orgID int64, model string) (int64, int64, error) {
+	contentType, err := r.client.GetMetadataContentType(orgID, model)
+	if err != nil {
+		return 0, 0, err
+	}
+	projectContentType, err := r.client.GetMetadataContentType(orgID, "project")
+	if err != nil {
+		return 0, 0, err
+	}
+	return contentType, projectContentType, nil
+}
+
+func (r *metadataModelFieldResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data MetadataModelFieldResourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	orgID := data.OrganisationID.ValueInt64()
+
+	contentType, projectContentType, err := r.contentTypes(orgID, data.ContentType.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read metadata content types, got error: %s", err))
+		return
+	}
+	clientModelField := data.ToClientMetadataModelField(contentType, projectContentType)
+
+	err = r.client.CreateMetadataModelField(orgID, clientModelField)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create metadata model field, got error: %s", err))
+		return
+	}
+	resourceData := MakeMetadataModelFieldResourceDataFromClientModelField(clientModelField, orgID, data.ContentType.ValueString(), projectContentType)
+
+	diags = resp.State.Set(ctx, &resourceData)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *metadataModelFieldResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data MetadataModelFieldResourceData
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	// Early return if the state is wrong
+	if diags.HasError() {
+		return
+	}
+	orgID := data.OrganisationID.ValueInt64()
+
+	clientModelField, err := r.client.GetMetadataModelField(orgID, data.ID.ValueInt64())
+	if err != nil {
+		if _, ok := err.(MetadataModelFieldNotFoundError); ok {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read metadata model field, got error: %s", err))
+		return
+	}
+	model, err := r.client.GetMetadataModel(orgID, clientModelField.ContentType)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read metadata content types, got error: %s", err))
+		return
+	}
+	projectContentType, err := r.client.GetMetadataContentType(orgID, "project")
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read metadata content types, got error: %s", err))
+		return
+	}
+	resourceData := MakeMetadataModelFieldResourceDataFromClientModelField(clientModelField, orgID, model, projectContentType)
+
+	diags = resp.State.Set(ctx, &resourceData)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *metadataModelFieldResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	//Get plan values
+	var plan MetadataModelFieldResourceData
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Update: Error reading plan data")
+		return
+	}
+	orgID := plan.OrganisationID.ValueInt64()
+
+	contentType, projectContentType, err := r.contentTypes(orgID, plan.ContentType.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read metadata content types, got error: %s", err))
+		return
+	}
+	clientModelField := plan.ToClientMetadataModelField(contentType, projectContentType)
+
+	// Keep the requirements on other content types than projects, since they
+	// are not managed
+	current, err := r.client.GetMetadataModelField(orgID, plan.ID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read metadata model field, got error: %s", err))
+		return
+	}
+	for _, requirement := range current.IsRequiredFor {
+		if requirement.ContentType != projectContentType {
+			clientModelField.IsRequiredFor = append(clientModelField.IsRequiredFor, requirement)
+		}
+	}
+
+	err = r.client.UpdateMetadataModelField(orgID, clientModelField)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update metadata model field, got error: %s", err))
+		return
+	}
+	resourceData := MakeMetadataModelFieldResourceDataFromClientModelField(clientModelField, orgID, plan.ContentType.ValueString(), projectContentType)
+
+	// Update the state with the new values
+	diags = resp.State.Set(ctx, &resourceData)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *metadataModelFieldResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Get current state
+	var state MetadataModelFieldResourceData
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Delete: Error reading state data")
+		return
+	}
+
+	err := r.client.DeleteMetadataModelField(state.OrganisationID.ValueInt64(), state.ID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete metadata model field, got error: %s", err))
+		return
+	}
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *metadataModelFieldResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importKey := strings.Split(req.ID, ",")
+	if len(importKey) == 2 {
+		orgID, orgErr := strconv.ParseInt(importKey[0], 10, 64)
+		modelFieldID, idErr := strconv.ParseInt(importKey[1], 10, 64)
+		if orgErr == nil && idErr == nil {
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organisation_id"), orgID)...)
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), modelFieldID)...)
+			return
+		}
+	}
+	resp.Diagnostics.AddError(
+		"Unexpected Import Identifier",
+		fmt.Sprintf("Expected import identifier with format: organisation_id,metadata_model_field_id Got: %q", req.ID),
+	)
+}
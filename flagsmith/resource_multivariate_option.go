@@ -22,6 +22,7 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces
 var _ resource.Resource = &multivariateResource{}
 var _ resource.ResourceWithImportState = &multivariateResource{}
+var _ resource.ResourceWithModifyPlan = &multivariateResource{}
 
 type multivariateResourceType struct{}
 
@@ -30,7 +31,7 @@ func newMultivariateResource() resource.Resource {
 }
 
 type multivariateResource struct {
-	client *flagsmithapi.Client
+	client *fsClient
 }
 
 func (r *multivariateResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -39,11 +40,11 @@ func (r *multivariateResource) Configure(ctx context.Context, req resource.Confi
 		return
 	}
 
-	client, ok := req.ProviderData.(*flagsmithapi.Client)
+	client, ok := req.ProviderData.(*fsClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *flagsmithapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -83,6 +84,11 @@ func (t *multivariateResource) Schema(ctx context.Context, req resource.SchemaRe
 				MarkdownDescription: "String value of the multivariate option if the type is `unicode`",
 				Optional:            true,
 			},
+			"sensitive_string_value": schema.StringAttribute{
+				MarkdownDescription: "String value of the multivariate option if the type is `unicode`, hidden from the plan output. Use it instead of `string_value` for secrets",
+				Optional:            true,
+				Sensitive:           true,
+			},
 			"integer_value": schema.Int64Attribute{
 				MarkdownDescription: "Integer value of the multivariate option if the type is `int`",
 				Optional:            true,
@@ -117,12 +123,47 @@ func (f *multivariateResource) ConfigValidators(ctx context.Context) []resource.
 	return []resource.ConfigValidator{
 		resourcevalidator.ExactlyOneOf(
 			path.MatchRoot("string_value"),
+			path.MatchRoot("sensitive_string_value"),
 			path.MatchRoot("integer_value"),
 			path.MatchRoot("boolean_value"),
 		),
 	}
 }
 
+// ModifyPlan errors if the string value of an option of a feature carrying the
+// `sensitive_feature_tag` of the provider is not set with `sensitive_string_value`
+func (r *multivariateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil || r.client.sensitiveFeatureTag == "" {
+		return
+	}
+	var plan MultivariateOptionResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.StringValue.IsNull() || plan.FeatureUUID.IsUnknown() {
+		return
+	}
+	feature, err := r.client.GetFeature(plan.FeatureUUID.ValueString())
+	if err != nil {
+		// The feature may not exist yet
+		if _, ok := err.(flagsmithapi.FeatureNotFoundError); ok {
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read feature, got error: %s", err))
+		return
+	}
+	sensitive, err := r.client.IsSensitiveFeature("", *feature.ProjectID, *feature.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read the tags of the feature, got error: %s", err))
+		return
+	}
+	if sensitive {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("string_value"),
+			"Sensitive Feature Value",
+			fmt.Sprintf("Feature %q is tagged %q, set the value of the option with sensitive_string_value instead", feature.Name, r.client.sensitiveFeatureTag),
+		)
+	}
+}
+
 func (r *multivariateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data MultivariateOptionResourceData
 
@@ -143,6 +184,9 @@ func (r *multivariateResource) Create(ctx context.Context, req resource.CreateRe
 	}
 
 	resourceData := NewMultivariateOptionFromClientOption(mvOption)
+	if !data.SensitiveStringValue.IsNull() {
+		resourceData.MarkSensitive()
+	}
 
 	diags = resp.State.Set(ctx, &resourceData)
 	resp.Diagnostics.Append(diags...)
@@ -169,6 +213,20 @@ func (r *multivariateResource) Read(ctx context.Context, req resource.ReadReques
 	}
 	resourceData := NewMultivariateOptionFromClientOption(mvOption)
 
+	// A value set with sensitive_string_value stays sensitive, and so do the
+	// values of the features tagged as such
+	sensitive := !data.SensitiveStringValue.IsNull()
+	if !sensitive {
+		sensitive, err = r.client.IsSensitiveFeature("", resourceData.ProjectID.ValueInt64(), resourceData.FeatureID.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read the tags of the feature, got error: %s", err))
+			return
+		}
+	}
+	if sensitive {
+		resourceData.MarkSensitive()
+	}
+
 	diags = resp.State.Set(ctx, &resourceData)
 	resp.Diagnostics.Append(diags...)
 
@@ -198,6 +256,7 @@ func (r *multivariateResource) Update(ctx context.Context, req resource.UpdateRe
 	// Update state with plan values
 	state.Type = plan.Type
 	state.StringValue = plan.StringValue
+	state.SensitiveStringValue = plan.SensitiveStringValue
 	state.IntegerValue = plan.IntegerValue
 	state.BooleanValue = plan.BooleanValue
 	state.DefaultPercentageAllocation = plan.DefaultPercentageAllocation
@@ -212,6 +271,9 @@ func (r *multivariateResource) Update(ctx context.Context, req resource.UpdateRe
 	}
 
 	resourceData := NewMultivariateOptionFromClientOption(mvOption)
+	if !plan.SensitiveStringValue.IsNull() {
+		resourceData.MarkSensitive()
+	}
 
 	// Update the state with the new values
 	diags = resp.State.Set(ctx, &resourceData)
@@ -3,7 +3,6 @@ package flagsmith
 import (
 	"context"
 	"fmt"
-	"github.com/Flagsmith/flagsmith-go-api-client"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -19,13 +18,14 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces
 var _ resource.Resource = &projectResource{}
 var _ resource.ResourceWithImportState = &projectResource{}
+var _ resource.ResourceWithModifyPlan = &projectResource{}
 
 func newProjectResource() resource.Resource {
 	return &projectResource{}
 }
 
 type projectResource struct {
-	client *flagsmithapi.Client
+	client *fsClient
 }
 
 func (r *projectResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -38,11 +38,11 @@ func (r *projectResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*flagsmithapi.Client)
+	client, ok := req.ProviderData.(*fsClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *flagsmithapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -125,6 +125,34 @@ func (t *projectResource) Schema(ctx context.Context, req resource.SchemaRequest
 	}
 }
 
+// ModifyPlan hands the planned feature rules of the project to the features
+// planned after it, so that they are validated against them
+func (r *projectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+	var plan ProjectResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.Name.IsUnknown() || plan.OrganisationID.IsUnknown() ||
+		plan.OnlyAllowLowerCaseFeatureNames.IsUnknown() || plan.FeatureNameRegex.IsUnknown() || plan.EnforceFeatureOwners.IsUnknown() {
+		return
+	}
+	rules := FeatureRules{
+		ProjectName:        plan.Name.ValueString(),
+		ProjectID:          plan.ID.ValueInt64(),
+		OrganisationID:     plan.OrganisationID.ValueInt64(),
+		OnlyAllowLowerCase: plan.OnlyAllowLowerCaseFeatureNames.ValueBool(),
+		Regex:              plan.FeatureNameRegex.ValueString(),
+		EnforceOwners:      plan.EnforceFeatureOwners.ValueBool(),
+	}
+	// The features of a new project can only refer to it by its unknown UUID
+	if plan.UUID.IsUnknown() || plan.ID.IsUnknown() {
+		r.client.SetPlannedFeatureRules(rules)
+		return
+	}
+	r.client.SetFeatureRules(plan.UUID.ValueString(), rules)
+}
+
 func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data ProjectResourceData
 
@@ -144,6 +172,7 @@ func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create project, got error: %s", err))
 		return
 	}
+	r.client.SetFeatureRules(clientProject.UUID, MakeFeatureRules(clientProject))
 	resourceData := MakeProjectResourceDataFromClientProject(clientProject)
 
 	diags = resp.State.Set(ctx, &resourceData)
@@ -191,6 +220,7 @@ func (r *projectResource) Update(ctx context.Context, req resource.UpdateRequest
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update project, got error: %s", err))
 		return
 	}
+	r.client.SetFeatureRules(clientProject.UUID, MakeFeatureRules(clientProject))
 
 	resourceData := MakeProjectResourceDataFromClientProject(clientProject)
 
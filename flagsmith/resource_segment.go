@@ -14,18 +14,20 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces
 var _ resource.Resource = &segmentResource{}
 var _ resource.ResourceWithImportState = &segmentResource{}
+var _ resource.ResourceWithModifyPlan = &segmentResource{}
 
 func newSegmentResource() resource.Resource {
 	return &segmentResource{}
 }
 
 type segmentResource struct {
-	client *flagsmithapi.Client
+	client *fsClient
 }
 
 func (r *segmentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -38,11 +40,11 @@ func (r *segmentResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*flagsmithapi.Client)
+	client, ok := req.ProviderData.(*fsClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *flagsmithapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -132,6 +134,7 @@ func (t *segmentResource) Schema(ctx context.Context, req resource.SchemaRequest
 				Required:            true,
 				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
 			},
+			"metadata": metadataSchema(MetadataModelSegment),
 			"rules": schema.ListNestedAttribute{
 				MarkdownDescription: "Rules for the segment",
 				Required:            true,
@@ -158,6 +161,58 @@ func (t *segmentResource) Schema(ctx context.Context, req resource.SchemaRequest
 
 }
 
+// ModifyPlan validates the metadata of new and updated segments against the
+// metadata fields of their organisation
+func (r *segmentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+	var projectUUID types.String
+	var metadata types.Map
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("project_uuid"), &projectUUID)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("metadata"), &metadata)...)
+	if resp.Diagnostics.HasError() || projectUUID.IsUnknown() || metadata.IsNull() {
+		return
+	}
+	// The feature rules of the project cache its organisation
+	rules, err := r.client.GetFeatureRules(projectUUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read project, got error: %s", err))
+		return
+	}
+	resp.Diagnostics.Append(validateMetadata(ctx, r.client, rules.OrganisationID, rules.ProjectID, MetadataModelSegment, metadata)...)
+}
+
+// clientMetadata returns the metadata of the segment as sent to Flagsmith, nil
+// if not managed
+func (r *segmentResource) clientMetadata(data *SegmentResourceData) ([]Metadata, error) {
+	if data.Metadata == nil {
+		return nil, nil
+	}
+	rules, err := r.client.GetFeatureRules(data.ProjectUUID.ValueString())
+	if err != nil {
+		return nil, err
+	}
+	return makeClientMetadata(r.client, rules.OrganisationID, MetadataModelSegment, data.Metadata)
+}
+
+// readMetadata sets the metadata of the segment, if managed
+func (r *segmentResource) readMetadata(data *SegmentResourceData) error {
+	if data.Metadata == nil {
+		return nil
+	}
+	rules, err := r.client.GetFeatureRules(data.ProjectUUID.ValueString())
+	if err != nil {
+		return err
+	}
+	clientMetadata, err := r.client.GetSegmentMetadata(data.ProjectID.ValueInt64(), data.ID.ValueInt64())
+	if err != nil {
+		return err
+	}
+	data.Metadata, err = makeMetadataData(r.client, rules.OrganisationID, MetadataModelSegment, clientMetadata)
+	return err
+}
+
 func (r *segmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data SegmentResourceData
 
@@ -167,15 +222,21 @@ func (r *segmentResource) Create(ctx context.Context, req resource.CreateRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	clientMetadata, err := r.clientMetadata(&data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve segment metadata, got error: %s", err))
+		return
+	}
 	clientSegment := data.ToClientSegment()
 
-	err := r.client.CreateSegment(clientSegment)
+	err = r.client.CreateSegmentWithMetadata(clientSegment, clientMetadata)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create segment, got error: %s", err))
 		return
 	}
 	resourceData := MakeSegmentResourceDataFromClientSegment(clientSegment)
+	resourceData.Metadata = data.Metadata
 
 	diags = resp.State.Set(ctx, &resourceData)
 	resp.Diagnostics.Append(diags...)
@@ -202,6 +263,11 @@ func (r *segmentResource) Read(ctx context.Context, req resource.ReadRequest, re
 
 	}
 	resourceData := MakeSegmentResourceDataFromClientSegment(segment)
+	resourceData.Metadata = data.Metadata
+	if err := r.readMetadata(&resourceData); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read segment metadata, got error: %s", err))
+		return
+	}
 
 	diags = resp.State.Set(ctx, &resourceData)
 	resp.Diagnostics.Append(diags...)
@@ -228,16 +294,22 @@ func (r *segmentResource) Update(ctx context.Context, req resource.UpdateRequest
 		tflog.Error(ctx, "Update: Error reading state data")
 		return
 	}
+	clientMetadata, err := r.clientMetadata(&plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve segment metadata, got error: %s", err))
+		return
+	}
 	// Generate API request body from plan
 	clientSegment := plan.ToClientSegment()
 
-	err := r.client.UpdateSegment(clientSegment)
+	err = r.client.UpdateSegmentWithMetadata(clientSegment, clientMetadata)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update segment, got error: %s", err))
 		return
 	}
 
 	resourceData := MakeSegmentResourceDataFromClientSegment(clientSegment)
+	resourceData.Metadata = plan.Metadata
 
 	// Update the state with the new values
 	diags = resp.State.Set(ctx, &resourceData)
@@ -0,0 +1,417 @@
+package flagsmith
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &segmentOverrideResource{}
+var _ resource.ResourceWithImportState = &segmentOverrideResource{}
+var _ resource.ResourceWithConfigValidators = &segmentOverrideResource{}
+var _ resource.ResourceWithModifyPlan = &segmentOverrideResource{}
+
+func newSegmentOverrideResource() resource.Resource {
+	return &segmentOverrideResource{}
+}
+
+type segmentOverrideResource struct {
+	client *fsClient
+}
+
+func (r *segmentOverrideResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_segment_override"
+}
+
+func (r *segmentOverrideResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*fsClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (t *segmentOverrideResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Flagsmith Segment override of a feature in an environment",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the featurestate of the segment override",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+			},
+			"uuid": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "UUID of the featurestate of the segment override",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"environment_key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Client side environment key associated with the environment",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"feature_id": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "ID of the feature",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+			},
+			"segment_id": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "ID of the segment. Changing this moves the override to the new segment by replacing it",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+			},
+			"segment_priority": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Priority of the segment override, `0` being the highest. If unspecified, Flagsmith assigns the lowest priority on creation",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+			},
+			"feature_state_value": featureStateValueSchema(),
+
+			"enabled": schema.BoolAttribute{
+				Required:            true,
+				MarkdownDescription: "Used for enabling/disabling the feature for the segment",
+			},
+			"environment_id": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the environment",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+			},
+			"feature_segment_id": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the feature_segment, used internally to bind the feature state to the segment",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+			},
+			"feature_version_uuid": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "UUID of the feature version published by the last apply, if the environment uses v2 feature versioning",
+			},
+		},
+	}
+}
+
+func (r *segmentOverrideResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("feature_state_value").AtName("string_value"),
+			path.MatchRoot("feature_state_value").AtName("sensitive_string_value"),
+			path.MatchRoot("feature_state_value").AtName("integer_value"),
+			path.MatchRoot("feature_state_value").AtName("boolean_value"),
+		),
+	}
+}
+
+func (r *segmentOverrideResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is planned on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan SegmentOverrideResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateSensitiveFeatureStateValue(r.client, plan.EnvironmentKey, plan.Feature, plan.FeatureStateValue)...)
+}
+
+// readSegmentOverride fetches the feature state of the segment override. If the
+// resource is being imported(i.e: uuid is not known yet), or if the environment
+// uses v2 feature versioning(i.e: every published version has its own feature
+// states), the override is looked up using the environment, feature and segment.
+func (r *segmentOverrideResource) readSegmentOverride(data *SegmentOverrideResourceData) (*flagsmithapi.FeatureState, error) {
+	versioned, err := r.client.IsV2Versioned(data.EnvironmentKey.ValueString())
+	if err != nil {
+		return nil, err
+	}
+	if data.UUID.ValueString() != "" && !versioned {
+		return r.client.GetFeatureState(data.UUID.ValueString())
+	}
+	environment, err := r.client.GetEnvironment(data.EnvironmentKey.ValueString())
+	if err != nil {
+		return nil, err
+	}
+	return r.client.GetSegmentOverride(environment.ID, data.Feature.ValueInt64(), data.Segment.ValueInt64())
+}
+
+// publishSegmentOverride applies the changes of the given feature version to a
+// versioned environment and reads the segment override back
+func (r *segmentOverrideResource) publishSegmentOverride(data *SegmentOverrideResourceData, version *FeatureVersion) (*SegmentOverrideResourceData, error) {
+	environment, err := r.client.GetEnvironment(data.EnvironmentKey.ValueString())
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.PublishFeatureVersion(environment.ID, data.Feature.ValueInt64(), version)
+	if err != nil {
+		return nil, err
+	}
+	featureState, err := r.client.GetSegmentOverride(environment.ID, data.Feature.ValueInt64(), data.Segment.ValueInt64())
+	if err != nil {
+		return nil, err
+	}
+	resourceData := MakeSegmentOverrideResourceDataFromClientFS(featureState)
+	resourceData.EnvironmentKey = data.EnvironmentKey
+	resourceData.FeatureVersion = types.StringValue(version.UUID)
+	if data.FeatureStateValue.IsSensitive() {
+		resourceData.FeatureStateValue.MarkSensitive()
+	}
+	return &resourceData, nil
+}
+
+func (r *segmentOverrideResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SegmentOverrideResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Creating an override reorders the other overrides of the feature
+	unlock := r.client.LockSegmentOverrides(data.EnvironmentKey.ValueString(), data.Feature.ValueInt64())
+	defer unlock()
+
+	clientFeatureState := data.ToClientFS()
+
+	versioned, err := r.client.IsV2Versioned(data.EnvironmentKey.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment versioning, got error: %s", err))
+		return
+	}
+	if versioned {
+		resourceData, err := r.publishSegmentOverride(&data, &FeatureVersion{
+			FeatureStatesToCreate: []FeatureVersionFeatureState{NewFeatureVersionFeatureState(clientFeatureState)},
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to publish segment override, got error: %s", err))
+			return
+		}
+		diags = resp.State.Set(ctx, resourceData)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	err = r.client.CreateSegmentOverride(clientFeatureState)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create segment override, got error: %s", err))
+		return
+	}
+
+	// Read the override back to load the final priority assigned by Flagsmith
+	featureState, err := r.client.GetFeatureState(clientFeatureState.UUID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read segment override after create, got error: %s", err))
+		return
+	}
+	resourceData := MakeSegmentOverrideResourceDataFromClientFS(featureState)
+	resourceData.EnvironmentKey = data.EnvironmentKey
+	if data.FeatureStateValue.IsSensitive() {
+		resourceData.FeatureStateValue.MarkSensitive()
+	}
+
+	diags = resp.State.Set(ctx, &resourceData)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *segmentOverrideResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SegmentOverrideResourceData
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	// Early return if the state is wrong
+	if diags.HasError() {
+		return
+	}
+
+	featureState, err := r.readSegmentOverride(&data)
+	if err != nil {
+		switch err.(type) {
+		case flagsmithapi.FeatureStateNotFoundError, FeatureSegmentNotFoundError:
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read segment override, got error: %s", err))
+		return
+	}
+	resourceData := MakeSegmentOverrideResourceDataFromClientFS(featureState)
+	resourceData.EnvironmentKey = data.EnvironmentKey
+	if !data.FeatureVersion.IsUnknown() {
+		resourceData.FeatureVersion = data.FeatureVersion
+	}
+
+	// A value set with sensitive_string_value stays sensitive, and so do the
+	// values of the features tagged as such
+	sensitive := data.FeatureStateValue.IsSensitive()
+	if !sensitive {
+		sensitive, err = r.client.IsSensitiveEnvironmentFeature(data.EnvironmentKey.ValueString(), data.Feature.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read the tags of the feature, got error: %s", err))
+			return
+		}
+	}
+	if sensitive {
+		resourceData.FeatureStateValue.MarkSensitive()
+	}
+
+	diags = resp.State.Set(ctx, &resourceData)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *segmentOverrideResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Get plan values
+	var plan SegmentOverrideResourceData
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Update: Error reading plan data")
+		return
+	}
+
+	// Get current state
+	var state SegmentOverrideResourceData
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Update: Error reading state data")
+		return
+	}
+
+	unlock := r.client.LockSegmentOverrides(state.EnvironmentKey.ValueString(), state.Feature.ValueInt64())
+	defer unlock()
+
+	// Generate API request body from plan
+	clientFeatureState := plan.ToClientFS()
+
+	// Load computed data from the state
+	clientFeatureState.ID = state.ID.ValueInt64()
+	featureSegment := state.FeatureSegment.ValueInt64()
+	clientFeatureState.FeatureSegment = &featureSegment
+	environment := state.Environment.ValueInt64()
+	clientFeatureState.Environment = &environment
+
+	versioned, err := r.client.IsV2Versioned(state.EnvironmentKey.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment versioning, got error: %s", err))
+		return
+	}
+	if versioned {
+		resourceData, err := r.publishSegmentOverride(&plan, &FeatureVersion{
+			FeatureStatesToUpdate: []FeatureVersionFeatureState{NewFeatureVersionFeatureState(clientFeatureState)},
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to publish segment override, got error: %s", err))
+			return
+		}
+		diags = resp.State.Set(ctx, resourceData)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	updateSegmentPriority := clientFeatureState.SegmentPriority != nil && !plan.SegmentPriority.Equal(state.SegmentPriority)
+	err = r.client.UpdateFeatureState(clientFeatureState, updateSegmentPriority)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update segment override, got error: %s", err))
+		return
+	}
+
+	// Read the override back since updating the priority can reorder the other overrides
+	featureState, err := r.client.GetFeatureState(state.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read segment override after update, got error: %s", err))
+		return
+	}
+	resourceData := MakeSegmentOverrideResourceDataFromClientFS(featureState)
+	resourceData.EnvironmentKey = plan.EnvironmentKey
+	if plan.FeatureStateValue.IsSensitive() {
+		resourceData.FeatureStateValue.MarkSensitive()
+	}
+
+	// Update the state with the new values
+	diags = resp.State.Set(ctx, &resourceData)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *segmentOverrideResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Get current state
+	var state SegmentOverrideResourceData
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Delete: Error reading state data")
+		return
+	}
+
+	unlock := r.client.LockSegmentOverrides(state.EnvironmentKey.ValueString(), state.Feature.ValueInt64())
+	defer unlock()
+
+	versioned, err := r.client.IsV2Versioned(state.EnvironmentKey.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment versioning, got error: %s", err))
+		return
+	}
+	if versioned {
+		err = r.client.PublishFeatureVersion(state.Environment.ValueInt64(), state.Feature.ValueInt64(), &FeatureVersion{
+			SegmentIDsToDeleteOverrides: []int64{state.Segment.ValueInt64()},
+		})
+	} else {
+		// Deleting the feature segment also deletes the feature state bound to it
+		err = r.client.DeleteFeatureSegment(state.FeatureSegment.ValueInt64())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete segment override, got error: %s", err))
+		return
+	}
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *segmentOverrideResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importKey := strings.Split(req.ID, ",")
+	if len(importKey) != 3 || importKey[0] == "" || importKey[1] == "" || importKey[2] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: environment_key,feature_id,segment_id Got: %q", req.ID),
+		)
+		return
+	}
+	featureID, err := strconv.ParseInt(importKey[1], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Unexpected Import Identifier", fmt.Sprintf("feature_id must be an integer, got: %q", importKey[1]))
+		return
+	}
+	segmentID, err := strconv.ParseInt(importKey[2], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Unexpected Import Identifier", fmt.Sprintf("segment_id must be an integer, got: %q", importKey[2]))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("environment_key"), importKey[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("feature_id"), featureID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("segment_id"), segmentID)...)
+}
@@ -0,0 +1,282 @@
+package flagsmith
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &segmentOverrideOrderResource{}
+var _ resource.ResourceWithImportState = &segmentOverrideOrderResource{}
+var _ resource.ResourceWithModifyPlan = &segmentOverrideOrderResource{}
+
+func newSegmentOverrideOrderResource() resource.Resource {
+	return &segmentOverrideOrderResource{}
+}
+
+type segmentOverrideOrderResource struct {
+	client *fsClient
+}
+
+func (r *segmentOverrideOrderResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_segment_override_order"
+}
+
+func (r *segmentOverrideOrderResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*fsClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *fsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (t *segmentOverrideOrderResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Authoritative ordering of the segment overrides of a feature in an environment. " +
+			"Segment overrides managed alongside this resource should leave `segment_priority` unset.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the ordering, in the format `environment_key,feature_id`",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"environment_key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Client side environment key associated with the environment",
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"environment_id": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the environment",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+			},
+			"feature_id": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "ID of the feature",
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+			},
+			"segment_ids": schema.ListAttribute{
+				Required:            true,
+				ElementType:         types.Int64Type,
+				MarkdownDescription: "IDs of the segments whose overrides are ordered, from the highest to the lowest priority. Every segment must already have an override for the feature in the environment, and every override of the feature in the environment must be listed",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.UniqueValues(),
+				},
+			},
+		},
+	}
+}
+
+// ModifyPlan errors if segment_ids leaves out some of the existing segment
+// overrides of the feature in the environment
+func (r *segmentOverrideOrderResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+	var environmentKey types.String
+	var featureID types.Int64
+	var segmentIDs types.List
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("environment_key"), &environmentKey)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("feature_id"), &featureID)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("segment_ids"), &segmentIDs)...)
+	if resp.Diagnostics.HasError() || environmentKey.IsUnknown() || featureID.IsUnknown() || segmentIDs.IsUnknown() || segmentIDs.IsNull() {
+		return
+	}
+	planned := make([]int64, 0, len(segmentIDs.Elements()))
+	for _, element := range segmentIDs.Elements() {
+		segmentID, ok := element.(types.Int64)
+		// Segments created in the same plan may be overridden by then
+		if !ok || segmentID.IsUnknown() {
+			return
+		}
+		planned = append(planned, segmentID.ValueInt64())
+	}
+
+	environment, err := r.client.GetEnvironment(environmentKey.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment, got error: %s", err))
+		return
+	}
+	featureSegments, err := r.client.GetFeatureSegments(environment.ID, featureID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read segment overrides, got error: %s", err))
+		return
+	}
+	if err := ValidateSegmentOrder(featureSegments, planned); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("segment_ids"), "Unlisted Segment Overrides", err.Error())
+	}
+}
+
+// reorder applies the ordering of the plan with a single request and reads
+// back the resulting order
+func (r *segmentOverrideOrderResource) reorder(ctx context.Context, plan *SegmentOverrideOrderResourceData) (*SegmentOverrideOrderResourceData, error) {
+	environment, err := r.client.GetEnvironment(plan.EnvironmentKey.ValueString())
+	if err != nil {
+		return nil, err
+	}
+	featureID := plan.Feature.ValueInt64()
+
+	unlock := r.client.LockSegmentOverrides(plan.EnvironmentKey.ValueString(), featureID)
+	defer unlock()
+
+	featureSegments, err := r.client.GetFeatureSegments(environment.ID, featureID)
+	if err != nil {
+		return nil, err
+	}
+	featureSegmentIDs, err := OrderFeatureSegments(featureSegments, plan.SegmentIDs())
+	if err != nil {
+		return nil, err
+	}
+	tflog.Debug(ctx, "Reordering segment overrides", map[string]interface{}{"feature_segment_ids": featureSegmentIDs})
+	err = r.client.ReorderFeatureSegments(featureSegmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	featureSegments, err = r.client.GetFeatureSegments(environment.ID, featureID)
+	if err != nil {
+		return nil, err
+	}
+	return makeSegmentOverrideOrderResourceData(plan.EnvironmentKey.ValueString(), environment.ID, featureID, SegmentIDsByPriority(featureSegments)), nil
+}
+
+func makeSegmentOverrideOrderResourceData(environmentKey string, environmentID, featureID int64, segmentIDs []int64) *SegmentOverrideOrderResourceData {
+	resourceData := SegmentOverrideOrderResourceData{
+		ID:             types.StringValue(fmt.Sprintf("%s,%d", environmentKey, featureID)),
+		EnvironmentKey: types.StringValue(environmentKey),
+		Environment:    types.Int64Value(environmentID),
+		Feature:        types.Int64Value(featureID),
+		Segments:       []types.Int64{},
+	}
+	for _, segmentID := range segmentIDs {
+		resourceData.Segments = append(resourceData.Segments, types.Int64Value(segmentID))
+	}
+	return &resourceData
+}
+
+func (r *segmentOverrideOrderResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SegmentOverrideOrderResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resourceData, err := r.reorder(ctx, &data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to order segment overrides, got error: %s", err))
+		return
+	}
+
+	diags = resp.State.Set(ctx, resourceData)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *segmentOverrideOrderResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SegmentOverrideOrderResourceData
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	// Early return if the state is wrong
+	if diags.HasError() {
+		return
+	}
+
+	environmentID := data.Environment.ValueInt64()
+	if data.Environment.IsNull() || data.Environment.IsUnknown() {
+		environment, err := r.client.GetEnvironment(data.EnvironmentKey.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read environment, got error: %s", err))
+			return
+		}
+		environmentID = environment.ID
+	}
+
+	featureSegments, err := r.client.GetFeatureSegments(environmentID, data.Feature.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read segment overrides, got error: %s", err))
+		return
+	}
+	if len(featureSegments) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resourceData := makeSegmentOverrideOrderResourceData(data.EnvironmentKey.ValueString(), environmentID, data.Feature.ValueInt64(), SegmentIDsByPriority(featureSegments))
+
+	diags = resp.State.Set(ctx, resourceData)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *segmentOverrideOrderResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Get plan values
+	var plan SegmentOverrideOrderResourceData
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		tflog.Error(ctx, "Update: Error reading plan data")
+		return
+	}
+
+	resourceData, err := r.reorder(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to order segment overrides, got error: %s", err))
+		return
+	}
+
+	// Update the state with the new values
+	diags = resp.State.Set(ctx, resourceData)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *segmentOverrideOrderResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The segment overrides keep their current priorities, only the resource is
+	// removed from the state
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *segmentOverrideOrderResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importKey := strings.Split(req.ID, ",")
+	if len(importKey) != 2 || importKey[0] == "" || importKey[1] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: environment_key,feature_id Got: %q", req.ID),
+		)
+		return
+	}
+	featureID, err := strconv.ParseInt(importKey[1], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Unexpected Import Identifier", fmt.Sprintf("feature_id must be an integer, got: %q", importKey[1]))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("environment_key"), importKey[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("feature_id"), featureID)...)
+}
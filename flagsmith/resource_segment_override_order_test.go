@@ -0,0 +1,156 @@
+package flagsmith_test
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccSegmentOverrideOrderResource(t *testing.T) {
+	featureName := acctest.RandString(10)
+	resourceName := "flagsmith_segment_override_order.test_order"
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccSegmentOverrideOrderResourceConfig(featureName, "first_override", "second_override"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "environment_key", environmentKey()),
+					resource.TestCheckResourceAttr(resourceName, "environment_id", strconv.Itoa(environmentID())),
+					resource.TestCheckResourceAttr(resourceName, "segment_ids.#", "2"),
+					resource.TestCheckResourceAttrPair(resourceName, "segment_ids.0", "flagsmith_segment.first_segment", "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "segment_ids.1", "flagsmith_segment.second_segment", "id"),
+					resource.TestCheckResourceAttrSet(resourceName, "feature_id"),
+				),
+			},
+
+			// ImportState testing
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: getSegmentOverrideOrderImportID(resourceName),
+			},
+
+			// Update testing
+			{
+				Config: testAccSegmentOverrideOrderResourceConfig(featureName, "second_override", "first_override"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "segment_ids.#", "2"),
+					resource.TestCheckResourceAttrPair(resourceName, "segment_ids.0", "flagsmith_segment.second_segment", "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "segment_ids.1", "flagsmith_segment.first_segment", "id"),
+				),
+			},
+
+			// Every override must be ordered, which is checked during the plan
+			{
+				Config:      testAccSegmentOverrideOrderResourceConfig(featureName, "second_override"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`are not listed in segment_ids`),
+			},
+		},
+	})
+}
+
+func getSegmentOverrideOrderImportID(n string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		featureID, err := getAttributefromState(s, n, "feature_id")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s,%s", environmentKey(), featureID), nil
+	}
+}
+
+func testAccSegmentOverrideOrderResourceConfig(featureName string, overrides ...string) string {
+	segmentIDs := []string{}
+	for _, override := range overrides {
+		segmentIDs = append(segmentIDs, fmt.Sprintf("    flagsmith_segment_override.%s.segment_id,", override))
+	}
+	return fmt.Sprintf(`
+provider "flagsmith" {
+}
+
+resource "flagsmith_segment" "first_segment" {
+  name         = "%[1]s_first"
+  project_uuid = "%[2]s"
+  rules = [
+    {
+      "rules" : [{
+        "conditions" : [{
+          "operator" : "EQUAL",
+          "property" : "device_type",
+          "value" : "mobile"
+        }],
+        "type" : "ANY"
+      }],
+      "type" : "ALL"
+    }
+  ]
+}
+
+resource "flagsmith_segment" "second_segment" {
+  name         = "%[1]s_second"
+  project_uuid = "%[2]s"
+  rules = [
+    {
+      "rules" : [{
+        "conditions" : [{
+          "operator" : "EQUAL",
+          "property" : "device_type",
+          "value" : "desktop"
+        }],
+        "type" : "ANY"
+      }],
+      "type" : "ALL"
+    }
+  ]
+}
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name = "%[1]s"
+  project_uuid = "%[2]s"
+  description  = "feature created for terraform segment override order test"
+  type         = "STANDARD"
+}
+
+resource "flagsmith_segment_override" "first_override" {
+  enabled         = true
+  environment_key = "%[3]s"
+  feature_id      = flagsmith_feature.test_feature.id
+  segment_id      = flagsmith_segment.first_segment.id
+  feature_state_value = {
+    type         = "unicode"
+    string_value = "first"
+  }
+}
+
+resource "flagsmith_segment_override" "second_override" {
+  enabled         = true
+  environment_key = "%[3]s"
+  feature_id      = flagsmith_feature.test_feature.id
+  segment_id      = flagsmith_segment.second_segment.id
+  feature_state_value = {
+    type         = "unicode"
+    string_value = "second"
+  }
+}
+
+resource "flagsmith_segment_override_order" "test_order" {
+  environment_key = "%[3]s"
+  feature_id      = flagsmith_feature.test_feature.id
+  segment_ids = [
+%[4]s
+  ]
+}
+
+`, featureName, projectUUID(), environmentKey(), strings.Join(segmentIDs, "\n"))
+}
@@ -0,0 +1,144 @@
+package flagsmith_test
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"strconv"
+	"testing"
+)
+
+func TestAccSegmentOverrideResource(t *testing.T) {
+	featureName := acctest.RandString(10)
+	resourceName := "flagsmith_segment_override.test_segment_override"
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccSegmentOverrideResourceConfig(featureName, "first_segment", "one", true, 0),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "environment_key", environmentKey()),
+					resource.TestCheckResourceAttr(resourceName, "environment_id", strconv.Itoa(environmentID())),
+					resource.TestCheckResourceAttr(resourceName, "feature_state_value.string_value", "one"),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "true"),
+					resource.TestCheckResourceAttr(resourceName, "segment_priority", "0"),
+					resource.TestCheckResourceAttrPair(resourceName, "segment_id", "flagsmith_segment.first_segment", "id"),
+
+					resource.TestCheckResourceAttrSet(resourceName, "feature_id"),
+					resource.TestCheckResourceAttrSet(resourceName, "feature_segment_id"),
+					resource.TestCheckResourceAttrSet(resourceName, "uuid"),
+				),
+			},
+
+			// ImportState testing
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: getSegmentOverrideImportID(resourceName),
+			},
+
+			// Update testing
+			{
+				Config: testAccSegmentOverrideResourceConfig(featureName, "first_segment", "two", false, 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "feature_state_value.string_value", "two"),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "false"),
+					resource.TestCheckResourceAttr(resourceName, "segment_priority", "1"),
+					resource.TestCheckResourceAttrPair(resourceName, "segment_id", "flagsmith_segment.first_segment", "id"),
+				),
+			},
+
+			// Move the override to another segment
+			{
+				Config: testAccSegmentOverrideResourceConfig(featureName, "second_segment", "two", false, 0),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "feature_state_value.string_value", "two"),
+					resource.TestCheckResourceAttr(resourceName, "segment_priority", "0"),
+					resource.TestCheckResourceAttrPair(resourceName, "segment_id", "flagsmith_segment.second_segment", "id"),
+				),
+			},
+		},
+	})
+}
+
+func getSegmentOverrideImportID(n string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		featureID, err := getAttributefromState(s, n, "feature_id")
+		if err != nil {
+			return "", err
+		}
+		segmentID, err := getAttributefromState(s, n, "segment_id")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s,%s,%s", environmentKey(), featureID, segmentID), nil
+	}
+}
+
+func testAccSegmentOverrideResourceConfig(featureName, segment, featureStateValue string, isEnabled bool, segmentPriority int) string {
+	return fmt.Sprintf(`
+provider "flagsmith" {
+}
+
+resource "flagsmith_segment" "first_segment" {
+  name         = "%[1]s_first"
+  project_uuid = "%[2]s"
+  rules = [
+    {
+      "rules" : [{
+        "conditions" : [{
+          "operator" : "EQUAL",
+          "property" : "device_type",
+          "value" : "mobile"
+        }],
+        "type" : "ANY"
+      }],
+      "type" : "ALL"
+    }
+  ]
+}
+
+resource "flagsmith_segment" "second_segment" {
+  name         = "%[1]s_second"
+  project_uuid = "%[2]s"
+  rules = [
+    {
+      "rules" : [{
+        "conditions" : [{
+          "operator" : "EQUAL",
+          "property" : "device_type",
+          "value" : "desktop"
+        }],
+        "type" : "ANY"
+      }],
+      "type" : "ALL"
+    }
+  ]
+}
+
+resource "flagsmith_feature" "test_feature" {
+  feature_name = "%[1]s"
+  project_uuid = "%[2]s"
+  description  = "feature created for terraform segment override test"
+  type         = "STANDARD"
+}
+
+resource "flagsmith_segment_override" "test_segment_override" {
+  enabled          = %[4]t
+  environment_key  = "%[3]s"
+  feature_id       = flagsmith_feature.test_feature.id
+  segment_id       = flagsmith_segment.%[5]s.id
+  segment_priority = %[6]d
+  feature_state_value = {
+    type         = "unicode"
+    string_value = "%[7]s"
+  }
+}
+
+`, featureName, projectUUID(), environmentKey(), isEnabled, segment, segmentPriority, featureStateValue)
+}
@@ -1,5 +1,17 @@
 package flagsmith
 
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
 // Difference returns a slice of 64-bit integers containing the elements of a that are not present in b.
 // If a or b is nil, they are treated as empty slices.
 func Difference(a, b *[]int64) []int64 {
@@ -24,3 +36,143 @@ func Difference(a, b *[]int64) []int64 {
 	}
 	return result
 }
+
+// MergeIDs returns the IDs of current without the ones of remove, followed by
+// the ones of add current misses
+func MergeIDs(current, add, remove []int64) []int64 {
+	kept := Difference(&current, &remove)
+	return append(kept, Difference(&add, &kept)...)
+}
+
+// remainingIDs returns the current IDs once the planned ones are added and
+// the ones of the state left out of the plan removed, as done on update
+func remainingIDs(current, plan, state *[]int64) []int64 {
+	var ids []int64
+	if current != nil {
+		ids = *current
+	}
+	if plan == nil || state == nil {
+		return ids
+	}
+	return MergeIDs(ids, *plan, Difference(state, plan))
+}
+
+// keyedMutex hands out one mutex per key, so that writes touching the same
+// Flagsmith object can be serialised while unrelated writes still run concurrently.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func newKeyedMutex() *keyedMutex {
+	return &keyedMutex{locks: map[string]*sync.Mutex{}}
+}
+
+// Lock acquires the mutex associated with key, creating it if required.
+func (m *keyedMutex) Lock(key string) {
+	m.mu.Lock()
+	lock, ok := m.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.locks[key] = lock
+	}
+	m.mu.Unlock()
+
+	lock.Lock()
+}
+
+// Unlock releases the mutex associated with key.
+func (m *keyedMutex) Unlock(key string) {
+	m.mu.Lock()
+	lock := m.locks[key]
+	m.mu.Unlock()
+
+	lock.Unlock()
+}
+
+// SegmentIDsByPriority returns the segment IDs of the given feature segments,
+// ordered from the highest(0) to the lowest priority. Feature segments without
+// a segment are skipped.
+func SegmentIDsByPriority(featureSegments []flagsmithapi.FeatureSegment) []int64 {
+	sorted := make([]flagsmithapi.FeatureSegment, len(featureSegments))
+	copy(sorted, featureSegments)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return priorityOf(sorted[i]) < priorityOf(sorted[j])
+	})
+	segmentIDs := make([]int64, 0, len(sorted))
+	for _, featureSegment := range sorted {
+		if featureSegment.Segment != nil {
+			segmentIDs = append(segmentIDs, *featureSegment.Segment)
+		}
+	}
+	return segmentIDs
+}
+
+// ValidateSegmentOrder returns an error if segmentIDs does not list the
+// segments of all the given feature segments
+func ValidateSegmentOrder(featureSegments []flagsmithapi.FeatureSegment, segmentIDs []int64) error {
+	listed := map[int64]bool{}
+	for _, segmentID := range segmentIDs {
+		listed[segmentID] = true
+	}
+	unlisted := []int64{}
+	for _, segmentID := range SegmentIDsByPriority(featureSegments) {
+		if !listed[segmentID] {
+			unlisted = append(unlisted, segmentID)
+		}
+	}
+	if len(unlisted) > 0 {
+		return fmt.Errorf("the overrides of segments %v are not listed in segment_ids, which must list all the segment overrides of the feature in the environment", unlisted)
+	}
+	return nil
+}
+
+// OrderFeatureSegments returns the IDs of the given feature segments in the
+// order described by segmentIDs, which must list the segments of all of them.
+func OrderFeatureSegments(featureSegments []flagsmithapi.FeatureSegment, segmentIDs []int64) ([]int64, error) {
+	featureSegmentIDs := map[int64]int64{}
+	for _, featureSegment := range featureSegments {
+		if featureSegment.Segment != nil && featureSegment.ID != nil {
+			featureSegmentIDs[*featureSegment.Segment] = *featureSegment.ID
+		}
+	}
+	ordered := make([]int64, 0, len(featureSegments))
+	for _, segmentID := range segmentIDs {
+		featureSegmentID, ok := featureSegmentIDs[segmentID]
+		if !ok {
+			return nil, fmt.Errorf("segment %d does not have an override for this feature in this environment", segmentID)
+		}
+		ordered = append(ordered, featureSegmentID)
+	}
+	if err := ValidateSegmentOrder(featureSegments, segmentIDs); err != nil {
+		return nil, err
+	}
+	return ordered, nil
+}
+
+func priorityOf(featureSegment flagsmithapi.FeatureSegment) int64 {
+	if featureSegment.Priority == nil {
+		return math.MaxInt64
+	}
+	return *featureSegment.Priority
+}
+
+// rfc3339Validator validates that a string attribute is an RFC3339 timestamp
+type rfc3339Validator struct{}
+
+func (v rfc3339Validator) Description(ctx context.Context) string {
+	return "value must be an RFC3339 timestamp, e.g: 2024-01-01T00:00:00Z"
+}
+
+func (v rfc3339Validator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v rfc3339Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := time.Parse(time.RFC3339, req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Timestamp", fmt.Sprintf("%s, got: %q", v.Description(ctx), req.ConfigValue.ValueString()))
+	}
+}
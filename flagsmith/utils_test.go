@@ -0,0 +1,175 @@
+package flagsmith
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Flagsmith/flagsmith-go-api-client"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyedMutexSerialisesSameKey(t *testing.T) {
+	// Given
+	m := newKeyedMutex()
+	counter := 0
+	maxConcurrent := 0
+	var wg sync.WaitGroup
+
+	// When
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.Lock("env/1")
+			defer m.Unlock("env/1")
+
+			counter++
+			if counter > maxConcurrent {
+				maxConcurrent = counter
+			}
+			counter--
+		}()
+	}
+	wg.Wait()
+
+	// Then
+	assert.Equal(t, 1, maxConcurrent)
+}
+
+func TestKeyedMutexDoesNotBlockOtherKeys(t *testing.T) {
+	// Given
+	m := newKeyedMutex()
+	m.Lock("env/1")
+	defer m.Unlock("env/1")
+
+	// When
+	done := make(chan struct{})
+	go func() {
+		m.Lock("env/2")
+		m.Unlock("env/2")
+		close(done)
+	}()
+
+	// Then
+	<-done
+}
+
+func makeFeatureSegment(id, segment, priority int64) flagsmithapi.FeatureSegment {
+	return flagsmithapi.FeatureSegment{ID: &id, Segment: &segment, Priority: &priority}
+}
+
+func TestMergeIDs(t *testing.T) {
+	// Given
+	current := []int64{1, 2, 3}
+
+	// When
+	merged := MergeIDs(current, []int64{3, 4}, []int64{2, 5})
+
+	// Then
+	assert.Equal(t, []int64{1, 3, 4}, merged)
+}
+
+func TestRemainingIDs(t *testing.T) {
+	// Given
+	current := []int64{1, 2, 3}
+	plan := []int64{2, 4}
+	state := []int64{1, 2}
+
+	// When
+	remaining := remainingIDs(&current, &plan, &state)
+	unmanaged := remainingIDs(&current, nil, &state)
+
+	// Then
+	assert.Equal(t, []int64{2, 3, 4}, remaining)
+	assert.Equal(t, []int64{1, 2, 3}, unmanaged)
+}
+
+func TestSegmentIDsByPriority(t *testing.T) {
+	// Given
+	featureSegments := []flagsmithapi.FeatureSegment{
+		makeFeatureSegment(10, 100, 2),
+		makeFeatureSegment(11, 101, 0),
+		makeFeatureSegment(12, 102, 1),
+	}
+
+	// When
+	segmentIDs := SegmentIDsByPriority(featureSegments)
+
+	// Then
+	assert.Equal(t, []int64{101, 102, 100}, segmentIDs)
+}
+
+func TestOrderFeatureSegments(t *testing.T) {
+	// Given
+	featureSegments := []flagsmithapi.FeatureSegment{
+		makeFeatureSegment(10, 100, 0),
+		makeFeatureSegment(11, 101, 1),
+		makeFeatureSegment(12, 102, 2),
+	}
+
+	// When
+	featureSegmentIDs, err := OrderFeatureSegments(featureSegments, []int64{102, 100, 101})
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{12, 10, 11}, featureSegmentIDs)
+
+	// When
+	_, err = OrderFeatureSegments(featureSegments, []int64{103})
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestOrderFeatureSegmentsWithUnlistedOverride(t *testing.T) {
+	// Given
+	featureSegments := []flagsmithapi.FeatureSegment{
+		makeFeatureSegment(10, 100, 0),
+		makeFeatureSegment(11, 101, 1),
+		makeFeatureSegment(12, 102, 2),
+	}
+
+	// When
+	_, err := OrderFeatureSegments(featureSegments, []int64{102, 100})
+
+	// Then
+	assert.ErrorContains(t, err, "the overrides of segments [101] are not listed in segment_ids")
+}
+
+func TestOrderFeatureSegmentsSkipsIncompleteFeatureSegments(t *testing.T) {
+	// Given
+	segment := int64(101)
+	featureSegments := []flagsmithapi.FeatureSegment{
+		makeFeatureSegment(10, 100, 0),
+		{Segment: &segment},
+		{},
+	}
+
+	// When
+	featureSegmentIDs, err := OrderFeatureSegments(featureSegments, []int64{100, 101})
+
+	// Then
+	assert.ErrorContains(t, err, "segment 101 does not have an override")
+	assert.Nil(t, featureSegmentIDs)
+	assert.NoError(t, ValidateSegmentOrder(featureSegments, []int64{100, 101}))
+	assert.ErrorContains(t, ValidateSegmentOrder(featureSegments, []int64{101}), "the overrides of segments [100]")
+}
+
+func TestRFC3339Validator(t *testing.T) {
+	for value, valid := range map[string]bool{
+		"2024-01-01T00:00:00Z":      true,
+		"2024-01-01T00:00:00+02:00": true,
+		"2024-01-01":                false,
+		"midnight":                  false,
+	} {
+		// When
+		resp := validator.StringResponse{}
+		rfc3339Validator{}.ValidateString(context.Background(), validator.StringRequest{ConfigValue: types.StringValue(value)}, &resp)
+
+		// Then
+		assert.Equal(t, !valid, resp.Diagnostics.HasError(), value)
+	}
+}